@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/seblegall/postmanify"
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v2"
+)
+
+//defaultConfigFile is the config file loaded from the working directory, unless another one is given with -config
+const defaultConfigFile = ".postmanify.yaml"
+
+//fileConfig represents a config file. Each value is overridden by the matching flag, when set.
+//Relative paths are relative to the directory of the config file.
+type fileConfig struct {
+	Input                  string                 `yaml:"input"`
+	Output                 string                 `yaml:"output"`
+	Host                   string                 `yaml:"host"`
+	Scheme                 string                 `yaml:"scheme"`
+	BasePath               string                 `yaml:"basePath"`
+	Headers                map[string]string      `yaml:"headers"`
+	DefaultAuth            *bool                  `yaml:"defaultAuth"`
+	Auth                   map[string]interface{} `yaml:"auth"`
+	GroupBy                string                 `yaml:"groupBy"`
+	NameBy                 string                 `yaml:"nameBy"`
+	NameTemplate           string                 `yaml:"nameTemplate"`
+	BodyMode               string                 `yaml:"bodyMode"`
+	MaxDepth               int                    `yaml:"maxDepth"`
+	RealisticData          bool                   `yaml:"realisticData"`
+	Seed                   int64                  `yaml:"seed"`
+	DynamicVariables       bool                   `yaml:"dynamicVariables"`
+	CustomDynamicVariables map[string]string      `yaml:"customDynamicVariables"`
+	FixturesDir            string                 `yaml:"fixturesDir"`
+	ExpandContentTypes     bool                   `yaml:"expandContentTypes"`
+	Indent                 string                 `yaml:"indent"`
+	Compact                bool                   `yaml:"compact"`
+	SortKeys               bool                   `yaml:"sortKeys"`
+	Filter                 fileFilter             `yaml:"filter"`
+	Environments           []fileEnvironment      `yaml:"environments"`
+	Hooks                  []string               `yaml:"hooks"`
+	FailOnWarning          bool                   `yaml:"failOnWarning"`
+
+	//dir is the directory of the config file
+	dir string
+}
+
+//fileFilter represents the operation filter of a config file
+type fileFilter struct {
+	Include           fileSelector `yaml:"include"`
+	Exclude           fileSelector `yaml:"exclude"`
+	ExcludeDeprecated bool         `yaml:"excludeDeprecated"`
+}
+
+//fileSelector represents an operation selector of a config file
+type fileSelector struct {
+	Tags         []string          `yaml:"tags"`
+	Paths        []string          `yaml:"paths"`
+	Methods      []string          `yaml:"methods"`
+	OperationIDs []string          `yaml:"operationIds"`
+	Extensions   map[string]string `yaml:"extensions"`
+}
+
+//fileEnvironment represents a postman environment written next to the collection
+type fileEnvironment struct {
+	Name   string            `yaml:"name"`
+	Output string            `yaml:"output"`
+	Values map[string]string `yaml:"values"`
+}
+
+//loadConfig reads a config file. A missing file is ignored, unless it is required.
+func loadConfig(path string, required bool) (*fileConfig, error) {
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := fileConfig{dir: filepath.Dir(path)}
+	if err := yaml.UnmarshalStrict(b, &cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+//path resolves a path of the config file from its directory
+func (cfg *fileConfig) path(path string) string {
+	if path == "" || path == stdio || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(cfg.dir, path)
+}
+
+//apply sets the flags from the config file values, unless they are set on the command line
+func (cfg *fileConfig) apply(set map[string]bool) error {
+
+	values := []struct {
+		flag  string
+		value string
+		dest  *string
+	}{
+		{"f", cfg.path(cfg.Input), &swagSpecFilepath},
+		{"o", cfg.path(cfg.Output), &pmanSpecFilepath},
+		{"host", cfg.Host, &host},
+		{"scheme", cfg.Scheme, &scheme},
+		{"base-path", cfg.BasePath, &basePath},
+		{"name-template", cfg.NameTemplate, &nameTemplate},
+		{"fixtures-dir", cfg.path(cfg.FixturesDir), &fixturesDir},
+		{"indent", cfg.Indent, &indent},
+	}
+	for _, s := range values {
+		if !set[s.flag] && s.value != "" {
+			*s.dest = s.value
+		}
+	}
+
+	choices := []struct {
+		flag  string
+		key   string
+		value string
+		dest  *choice
+	}{
+		{"group-by", "groupBy", cfg.GroupBy, &groupBy},
+		{"name-by", "nameBy", cfg.NameBy, &naming},
+		{"body-mode", "bodyMode", cfg.BodyMode, &bodyMode},
+	}
+	for _, c := range choices {
+		if set[c.flag] || c.value == "" {
+			continue
+		}
+		if err := c.dest.Set(c.value); err != nil {
+			return fmt.Errorf("%s %s", c.key, err)
+		}
+	}
+
+	if !set["max-depth"] && cfg.MaxDepth != 0 {
+		maxDepth = cfg.MaxDepth
+	}
+	if !set["seed"] && cfg.Seed != 0 {
+		seed = cfg.Seed
+	}
+
+	flags := []struct {
+		flag  string
+		value bool
+		dest  *bool
+	}{
+		{"realistic-data", cfg.RealisticData, &realisticData},
+		{"dynamic-variables", cfg.DynamicVariables, &dynamicVariables},
+		{"expand-content-types", cfg.ExpandContentTypes, &expandContentTypes},
+		{"compact", cfg.Compact, &compact},
+		{"sort-keys", cfg.SortKeys, &sortKeys},
+		{"fail-on-warning", cfg.FailOnWarning, &failOnWarning},
+	}
+	for _, f := range flags {
+		if !set[f.flag] {
+			*f.dest = f.value
+		}
+	}
+
+	//dynamic variables set on the command line override the ones of the config file for the same format
+	for key, value := range cfg.CustomDynamicVariables {
+		if _, ok := dynamicVariableMap[key]; !ok {
+			dynamicVariableMap[key] = value
+		}
+	}
+
+	//headers set on the command line override the ones of the config file with the same name
+	for key, value := range cfg.Headers {
+		if _, ok := headers[key]; !ok {
+			headers[key] = postman2.Header{Key: key, Value: value}
+		}
+	}
+
+	if cfg.Auth != nil {
+		b, err := json.Marshal(jsonValue(cfg.Auth))
+		if err != nil {
+			return fmt.Errorf("auth %s", err)
+		}
+		var a postman2.Auth
+		if err := json.Unmarshal(b, &a); err != nil || a.Type == "" {
+			return fmt.Errorf("auth must be a Postman auth object")
+		}
+		auth = &a
+	}
+
+	//the default Authorization header is replaced by the auth of the config file
+	if !set["no-default-auth"] {
+		noDefaultAuth = cfg.Auth != nil || (cfg.DefaultAuth != nil && !*cfg.DefaultAuth)
+	}
+
+	selectors := []struct {
+		file *fileSelector
+		dest *postmanify.OperationSelector
+		kind string
+	}{
+		{&cfg.Filter.Include, &filter.Include, "include"},
+		{&cfg.Filter.Exclude, &filter.Exclude, "exclude"},
+	}
+	for _, s := range selectors {
+		lists := []struct {
+			flag  string
+			value []string
+			dest  *[]string
+		}{
+			{s.kind + "-tag", s.file.Tags, &s.dest.Tags},
+			{s.kind + "-path", s.file.Paths, &s.dest.Paths},
+			{s.kind + "-method", s.file.Methods, &s.dest.Methods},
+			{s.kind + "-operation", s.file.OperationIDs, &s.dest.OperationIDs},
+		}
+		for _, l := range lists {
+			if !set[l.flag] {
+				*l.dest = l.value
+			}
+		}
+		if !set[s.kind+"-extension"] {
+			for key, value := range s.file.Extensions {
+				s.dest.Extensions[key] = value
+			}
+		}
+	}
+	if !set["exclude-deprecated"] {
+		filter.ExcludeDeprecated = cfg.Filter.ExcludeDeprecated
+	}
+
+	for _, env := range cfg.Environments {
+		if env.Name == "" {
+			return fmt.Errorf("environments must have a name")
+		}
+		output := env.Output
+		if output == "" {
+			output = env.Name + ".postman_environment.json"
+		}
+		environments = append(environments, fileEnvironment{Name: env.Name, Output: cfg.path(output), Values: env.Values})
+	}
+
+	//hooks run arbitrary commands : the ones of a config file found in the working directory need an explicit opt-in
+	if len(cfg.Hooks) > 0 && !set["config"] && !allowHooks {
+		return fmt.Errorf("hooks are only run from a config file given with -config, or with -allow-hooks")
+	}
+	for _, command := range cfg.Hooks {
+		hooks = append(hooks, hookCommand(command, cfg.dir))
+	}
+
+	return nil
+}
+
+//jsonValue converts a yaml value, whose maps may have any key, into a value which may be encoded as json
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[fmt.Sprint(key)] = jsonValue(value)
+		}
+		return m
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[key] = jsonValue(value)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, value := range v {
+			s[i] = jsonValue(value)
+		}
+		return s
+	}
+	return v
+}
+
+//hookCommand transforms the collection with a shell command, run from a directory.
+//The command reads the collection as json on its stdin, and writes the transformed collection on its stdout.
+//The transformed collection may only hold the fields of postman2.Collection : a hook fails rather than dropping others.
+func hookCommand(command, dir string) postmanify.CollectionTransformer {
+	return postmanify.CollectionTransformerFunc(func(col *postman2.Collection, swag *spec.Swagger) error {
+
+		in, err := json.Marshal(col)
+		if err != nil {
+			return err
+		}
+
+		var out bytes.Buffer
+		cmd := hookShell(command)
+		cmd.Dir = dir
+		cmd.Stdin = bytes.NewReader(in)
+		cmd.Stdout = &out
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("hook %q: %s", command, err)
+		}
+
+		//fields which are not part of the collection model would be lost : they are rejected instead
+		var transformed postman2.Collection
+		decoder := json.NewDecoder(&out)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&transformed); err != nil {
+			return fmt.Errorf("hook %q must write a postman collection: %s", command, err)
+		}
+		*col = transformed
+
+		return nil
+	})
+}
+
+//hookShell returns the command running a hook with the shell of the platform
+func hookShell(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+//writeEnvironments writes the postman environments of the config file
+func writeEnvironments() error {
+	for _, env := range environments {
+		b, err := json.MarshalIndent(postman2.NewEnvironment(env.Name, env.Values), "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeOutput(env.Output, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seblegall/postmanify"
+	"github.com/seblegall/postmanify/postman2"
+)
+
+func TestLoadConfig(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "postmanify")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, defaultConfigFile)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`
+input: api/swagger.json
+output: /tmp/collection.json
+host: "{{baseUrl}}"
+scheme: https
+headers:
+  X-Api-Key: "{{api_key}}"
+  X-Client: file
+auth:
+  type: bearer
+  bearer:
+    - key: token
+      value: "{{token}}"
+groupBy: path
+nameTemplate: "{method} {path}"
+maxDepth: 3
+realisticData: true
+seed: 42
+dynamicVariables: true
+customDynamicVariables:
+  uuid: "{{$randomUUID}}"
+  email: "{{$email}}"
+fixturesDir: fixtures
+expandContentTypes: true
+indent: "    "
+compact: true
+sortKeys: true
+filter:
+  include:
+    tags: [public]
+    extensions:
+      x-public: "true"
+  excludeDeprecated: true
+environments:
+  - name: staging
+    values:
+      baseUrl: https://staging.example.com
+hooks:
+  - cat
+`), 0644))
+
+	missing, err := loadConfig(filepath.Join(dir, "missing.yaml"), false)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	_, err = loadConfig(filepath.Join(dir, "missing.yaml"), true)
+	assert.Error(t, err)
+
+	cfg, err := loadConfig(path, true)
+	assert.NoError(t, err)
+
+	set := parseFlags(t, "--config", path, "--host", "localhost:8080", "--header", "X-Client: flag", "--dynamic-variable", "email={{$randomEmail}}", "--compact=false")
+	assert.NoError(t, cfg.apply(set))
+
+	assert.Equal(t, filepath.Join(dir, "api", "swagger.json"), swagSpecFilepath)
+	assert.Equal(t, "/tmp/collection.json", pmanSpecFilepath)
+	assert.Equal(t, "localhost:8080", host)
+	assert.Equal(t, "https", scheme)
+	assert.Equal(t, headerMap{
+		"X-Api-Key": {Key: "X-Api-Key", Value: "{{api_key}}"},
+		"X-Client":  {Key: "X-Client", Value: "flag"},
+	}, headers)
+	assert.Equal(t, &postman2.Auth{Type: "bearer", Attributes: []postman2.AuthAttribute{{Key: "token", Value: "{{token}}"}}}, auth)
+	assert.True(t, noDefaultAuth)
+	assert.Equal(t, "path", groupBy.value)
+	assert.Equal(t, "{method} {path}", nameTemplate)
+	assert.Equal(t, 3, maxDepth)
+	assert.True(t, realisticData)
+	assert.Equal(t, int64(42), seed)
+	assert.True(t, dynamicVariables)
+	assert.Equal(t, keyValueMap{"uuid": "{{$randomUUID}}", "email": "{{$randomEmail}}"}, dynamicVariableMap)
+	assert.Equal(t, filepath.Join(dir, "fixtures"), fixturesDir)
+	assert.True(t, expandContentTypes)
+	assert.Equal(t, "    ", indent)
+	assert.False(t, compact)
+	assert.True(t, sortKeys)
+	assert.Equal(t, []string{"public"}, filter.Include.Tags)
+	assert.Equal(t, map[string]string{"x-public": "true"}, filter.Include.Extensions)
+	assert.True(t, filter.ExcludeDeprecated)
+	assert.Equal(t, []fileEnvironment{{
+		Name:   "staging",
+		Output: filepath.Join(dir, "staging.postman_environment.json"),
+		Values: map[string]string{"baseUrl": "https://staging.example.com"},
+	}}, environments)
+	assert.Len(t, hooks, 1)
+
+	col := postman2.NewCollection("title", "description")
+	assert.NoError(t, hooks[0].TransformCollection(&col, nil))
+	assert.Equal(t, "title", col.Info.Name)
+}
+
+func TestLoadInvalidConfig(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "postmanify")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dataset := []struct {
+		content string
+		load    bool
+	}{
+		{content: "hots: localhost", load: false},
+		{content: "groupBy: folder", load: true},
+		{content: "auth: {bearer: []}", load: true},
+		{content: "environments: [{output: env.json}]", load: true},
+		{content: "hooks: [cat]", load: true},
+	}
+
+	for _, data := range dataset {
+		path := filepath.Join(dir, defaultConfigFile)
+		assert.NoError(t, ioutil.WriteFile(path, []byte(data.content), 0644))
+
+		cfg, err := loadConfig(path, true)
+		if !data.load {
+			assert.Error(t, err, data.content)
+			continue
+		}
+		assert.NoError(t, err, data.content)
+		assert.Error(t, cfg.apply(parseFlags(t)), data.content)
+	}
+}
+
+func TestConfigHooks(t *testing.T) {
+
+	cfg := fileConfig{Hooks: []string{"cat"}}
+
+	dataset := []struct {
+		args     []string
+		expected bool
+	}{
+		{args: []string{}, expected: false},
+		{args: []string{"--config", defaultConfigFile}, expected: true},
+		{args: []string{"--allow-hooks"}, expected: true},
+	}
+
+	for _, data := range dataset {
+		err := cfg.apply(parseFlags(t, data.args...))
+		if !data.expected {
+			assert.Error(t, err, data.args)
+			assert.Empty(t, hooks, data.args)
+			continue
+		}
+		assert.NoError(t, err, data.args)
+		assert.Len(t, hooks, 1, data.args)
+	}
+}
+
+func TestHookCommand(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "postmanify")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dataset := []struct {
+		output   string
+		expected postman2.Collection
+		err      bool
+	}{
+		{
+			output: `{
+				"info": {"name": "hooked"},
+				"item": [{"name": "users", "variable": [{"key": "userId", "value": 1}], "auth": {"type": "noauth"}}],
+				"event": [{"listen": "prerequest", "script": {"type": "text/javascript", "exec": ["pm.variables.set('token', 'x')"]}}],
+				"variable": [{"key": "baseUrl", "value": "https://api.example.com", "type": "string"}],
+				"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]}
+			}`,
+			expected: postman2.Collection{
+				Info: postman2.CollectionInfo{Name: "hooked"},
+				Item: []postman2.FolderItem{{
+					Name:     "users",
+					Variable: []postman2.Variable{{Key: "userId", Value: float64(1)}},
+					Auth:     &postman2.Auth{Type: "noauth"},
+				}},
+				Event:    []postman2.Event{{Listen: "prerequest", Script: postman2.Script{Type: "text/javascript", Exec: []string{"pm.variables.set('token', 'x')"}}}},
+				Variable: []postman2.Variable{{Key: "baseUrl", Value: "https://api.example.com", Type: "string"}},
+				Auth:     &postman2.Auth{Type: "bearer", Attributes: []postman2.AuthAttribute{{Key: "token", Value: "{{token}}"}}},
+			},
+		},
+		{
+			output: `{"info": {"name": "hooked"}, "item": [], "protocolProfileBehavior": {"disableBodyPruning": true}}`,
+			err:    true,
+		},
+		{
+			output: `not a collection`,
+			err:    true,
+		},
+	}
+
+	for _, data := range dataset {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "collection.json"), []byte(data.output), 0644))
+
+		col := postman2.NewCollection("title", "description")
+		err := hookCommand("cat collection.json", dir).TransformCollection(&col, nil)
+		if data.err {
+			assert.Error(t, err, data.output)
+			continue
+		}
+		assert.NoError(t, err, data.output)
+		assert.Equal(t, data.expected, col)
+	}
+}
+
+func TestHookCommandRoundTrip(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "round trip", "version": "1.0"},
+		"host": "api.example.com",
+		"paths": {
+			"/users/{id}": {
+				"put": {
+					"tags": ["users"],
+					"consumes": ["application/json"],
+					"x-postman-auth": {"type": "basic", "basic": [{"key": "username", "value": "{{user}}"}]},
+					"x-postman-script": {"test": ["pm.test('ok')"]},
+					"parameters": [
+						{"in": "path", "name": "id", "type": "integer", "required": true},
+						{"in": "query", "name": "verbose", "type": "boolean"},
+						{"in": "header", "name": "X-Trace", "type": "string"},
+						{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}
+					]
+				}
+			},
+			"/users": {
+				"post": {
+					"tags": ["users"],
+					"consumes": ["multipart/form-data"],
+					"parameters": [
+						{"in": "formData", "name": "name", "type": "string"},
+						{"in": "formData", "name": "avatar", "type": "file"}
+					]
+				}
+			}
+		}
+	}`)
+
+	out, _, err := postmanify.NewConverter(postmanify.Config{}).Convert(swag)
+	assert.NoError(t, err)
+
+	//a hook writing its input as is keeps the whole collection
+	var col postman2.Collection
+	assert.NoError(t, json.Unmarshal(out, &col))
+	assert.NoError(t, hookCommand("cat", "").TransformCollection(&col, nil))
+
+	b, err := json.Marshal(col)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(out), string(b))
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/seblegall/postmanify/postman2"
+)
+
+//stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+//keyValueMap is a repeatable flag of pairs, such as vendor extensions, defined as key=value or as a key only
+type keyValueMap map[string]string
+
+func (m keyValueMap) String() string {
+	var pairs []string
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m keyValueMap) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	m[strings.TrimSpace(parts[0])] = ""
+	if len(parts) == 2 {
+		m[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return nil
+}
+
+//headerMap is a repeatable flag of headers, defined as "Key: Value"
+type headerMap map[string]postman2.Header
+
+func (m headerMap) String() string {
+	var headers []string
+	for _, header := range m {
+		headers = append(headers, header.Key+": "+header.Value)
+	}
+	return strings.Join(headers, ",")
+}
+
+func (m headerMap) Set(value string) error {
+	parts := strings.SplitN(value, ":", 2)
+	key := strings.TrimSpace(parts[0])
+	if len(parts) != 2 || key == "" {
+		return fmt.Errorf("%q must be defined as Key: Value", value)
+	}
+	m[key] = postman2.Header{Key: key, Value: strings.TrimSpace(parts[1])}
+	return nil
+}
+
+//choice is a string flag restricted to a set of values
+type choice struct {
+	value   string
+	choices []string
+}
+
+func (c *choice) String() string {
+	return c.value
+}
+
+func (c *choice) Set(value string) error {
+	for _, choice := range c.choices {
+		if value == choice {
+			c.value = value
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(c.choices, ", "))
+}
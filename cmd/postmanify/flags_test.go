@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seblegall/postmanify"
+	"github.com/seblegall/postmanify/postman2"
+)
+
+//parseFlags parses the command line arguments on a new flag set, and returns the names of the flags set
+func parseFlags(t *testing.T, args ...string) map[string]bool {
+
+	fs := flag.NewFlagSet("postmanify", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	registerFlags(fs)
+	assert.NoError(t, fs.Parse(args))
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	return set
+}
+
+func TestConverterConfigDefaults(t *testing.T) {
+
+	parseFlags(t)
+
+	assert.Equal(t, postmanify.Config{
+		PostmanHeaders: map[string]postman2.Header{"Authorization": defaultAuthorization},
+		Filter: postmanify.OperationFilter{
+			Include: postmanify.OperationSelector{Extensions: map[string]string{}},
+			Exclude: postmanify.OperationSelector{Extensions: map[string]string{}},
+		},
+		GroupBy:  postmanify.GroupByTag,
+		Naming:   postmanify.NameByPath,
+		BodyMode: postmanify.BodyModeAuto,
+	}, converterConfig())
+	assert.Equal(t, "swagger.json", swagSpecFilepath)
+	assert.Equal(t, "postman_collection.json", pmanSpecFilepath)
+}
+
+func TestConverterConfigFlags(t *testing.T) {
+
+	parseFlags(t,
+		"--host", "localhost:8080",
+		"--scheme", "https",
+		"--base-path", "/v2",
+		"--header", "X-Api-Key: {{api_key}}",
+		"--header", "X-Client:cli",
+		"--no-default-auth",
+		"--group-by", "path",
+		"--name-by", "operationId",
+		"--name-template", "{method} {path}",
+		"--body-mode", "urlencoded",
+		"--max-depth", "3",
+		"--realistic-data",
+		"--seed", "42",
+		"--dynamic-variables",
+		"--dynamic-variable", "uuid={{$randomUUID}}",
+		"--fixtures-dir", "fixtures",
+		"--expand-content-types",
+		"--indent", "\t",
+		"--compact",
+		"--sort-keys",
+		"--include-tag", "users",
+		"--exclude-extension", "x-internal",
+		"--exclude-deprecated",
+		"-f", "-",
+		"-o", "-",
+	)
+
+	cfg := converterConfig()
+
+	assert.Equal(t, "localhost:8080", cfg.Hostname)
+	assert.Equal(t, "https", cfg.Schema)
+	assert.Equal(t, "/v2", cfg.BasePath)
+	assert.Equal(t, map[string]postman2.Header{
+		"X-Api-Key": {Key: "X-Api-Key", Value: "{{api_key}}"},
+		"X-Client":  {Key: "X-Client", Value: "cli"},
+	}, cfg.PostmanHeaders)
+	assert.Equal(t, postmanify.GroupByPath, cfg.GroupBy)
+	assert.Equal(t, postmanify.NameByOperationID, cfg.Naming)
+	assert.Equal(t, "{method} {path}", cfg.NameTemplate)
+	assert.Equal(t, postmanify.BodyModeURLEncoded, cfg.BodyMode)
+	assert.Equal(t, 3, cfg.MaxDepth)
+	assert.True(t, cfg.RealisticData)
+	assert.Equal(t, int64(42), cfg.Seed)
+	assert.Equal(t, "{{$randomUUID}}", cfg.DynamicVariables["uuid"])
+	assert.Equal(t, postmanify.DefaultDynamicVariables["email"], cfg.DynamicVariables["email"])
+	assert.Equal(t, "fixtures", cfg.FixturesDir)
+	assert.True(t, cfg.ExpandContentTypes)
+	assert.Equal(t, postmanify.EncodeOptions{Indent: "\t", Compact: true, SortKeys: true}, cfg.Encoding)
+	assert.Equal(t, []string{"users"}, cfg.Filter.Include.Tags)
+	assert.Equal(t, map[string]string{"x-internal": ""}, cfg.Filter.Exclude.Extensions)
+	assert.True(t, cfg.Filter.ExcludeDeprecated)
+	assert.Equal(t, stdio, swagSpecFilepath)
+	assert.Equal(t, stdio, pmanSpecFilepath)
+
+	//custom dynamic variables may be used without the default ones
+	parseFlags(t, "--dynamic-variable", "email={{$randomEmail}}")
+	assert.Equal(t, map[string]string{"email": "{{$randomEmail}}"}, converterConfig().DynamicVariables)
+}
+
+func TestInvalidFlags(t *testing.T) {
+
+	dataset := [][]string{
+		{"--header", "X-Api-Key"},
+		{"--header", ": value"},
+		{"--group-by", "folder"},
+		{"--name-by", "title"},
+		{"--body-mode", "json"},
+		{"--max-depth", "deep"},
+	}
+
+	for _, args := range dataset {
+		fs := flag.NewFlagSet("postmanify", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		registerFlags(fs)
+		assert.Error(t, fs.Parse(args), args)
+	}
+}
@@ -1,42 +1,265 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"io/ioutil"
+	"os"
 
 	"github.com/seblegall/postmanify"
 	"github.com/seblegall/postmanify/postman2"
 )
 
+//Exit codes
+const (
+	exitError              = 1
+	exitInput              = 2
+	exitOutput             = 3
+	exitInvalidSpec        = 4
+	exitUnsupportedVersion = 5
+	exitWarnings           = 6
+)
+
+//stdio is the file path reading the swagger file from stdin, or writing the postman collection to stdout
+const stdio = "-"
+
+//defaultAuthorization is the Authorization header added to each request, unless disabled
+var defaultAuthorization = postman2.Header{Key: "Authorization", Value: "Bearer {{my_access_token}}"}
+
 var (
-	swagSpecFilepath string
-	pmanSpecFilepath string
-	host string
+	configFile         string
+	swagSpecFilepath   string
+	pmanSpecFilepath   string
+	host               string
+	scheme             string
+	basePath           string
+	headers            headerMap
+	noDefaultAuth      bool
+	groupBy            choice
+	naming             choice
+	nameTemplate       string
+	bodyMode           choice
+	maxDepth           int
+	realisticData      bool
+	seed               int64
+	dynamicVariables   bool
+	dynamicVariableMap keyValueMap
+	fixturesDir        string
+	expandContentTypes bool
+	indent             string
+	compact            bool
+	sortKeys           bool
+	failOnWarning      bool
+	allowHooks         bool
+	filter             postmanify.OperationFilter
+
+	//options only defined in the config file
+	auth         *postman2.Auth
+	environments []fileEnvironment
+	hooks        []postmanify.CollectionTransformer
 )
 
+//registerFlags defines the flags of the command on a flag set, and resets the options to their default values
+func registerFlags(fs *flag.FlagSet) {
+
+	headers = headerMap{}
+	groupBy = choice{value: string(postmanify.GroupByTag), choices: []string{string(postmanify.GroupByTag), string(postmanify.GroupByPath)}}
+	naming = choice{value: string(postmanify.NameByPath), choices: []string{string(postmanify.NameByPath), string(postmanify.NameBySummary), string(postmanify.NameByOperationID)}}
+	bodyMode = choice{value: "auto", choices: []string{"auto", string(postmanify.BodyModeRaw), string(postmanify.BodyModeURLEncoded), string(postmanify.BodyModeFormData)}}
+	dynamicVariableMap = keyValueMap{}
+	filter = postmanify.OperationFilter{
+		Include: postmanify.OperationSelector{Extensions: map[string]string{}},
+		Exclude: postmanify.OperationSelector{Extensions: map[string]string{}},
+	}
+	auth = nil
+	environments = nil
+	hooks = nil
+
+	fs.StringVar(&configFile, "config", defaultConfigFile, `The config file, whose values are overridden by flags. Ignored when the default one is missing.`)
+	fs.StringVar(&host, "host", "", `The hostname for the API, such as localhost:8080 or {{baseUrl}}. Default is the swagger host.`)
+	fs.StringVar(&scheme, "scheme", "", `The protocol for the API. Default is the first swagger scheme, or http.`)
+	fs.StringVar(&basePath, "base-path", "", `The base path for the API. Default is the swagger basePath.`)
+	fs.Var(headers, "header", `A header added to each request, as "Key: Value". May be repeated.`)
+	fs.BoolVar(&noDefaultAuth, "no-default-auth", false, `Do not add the "Authorization: Bearer {{my_access_token}}" header to each request`)
+	fs.Var(&groupBy, "group-by", `Group requests into folders by tag or by path`)
+	fs.Var(&naming, "name-by", `Name requests after their path, summary or operationId`)
+	fs.StringVar(&nameTemplate, "name-template", "", `Name requests after a template, such as "{method} {path}", with {method}, {path}, {summary}, {operationId} and {tag} placeholders`)
+	fs.Var(&bodyMode, "body-mode", `Mode of request bodies: auto, raw, urlencoded or formdata`)
+	fs.IntVar(&maxDepth, "max-depth", 0, `The maximum nesting depth of generated request bodies. Default is 10.`)
+	fs.BoolVar(&realisticData, "realistic-data", false, `Generate realistic sample values (names, emails, countries...) instead of constant values`)
+	fs.Int64Var(&seed, "seed", 0, `The seed of realistic sample values. A given seed always generates the same values.`)
+	fs.BoolVar(&dynamicVariables, "dynamic-variables", false, `Use Postman dynamic variables, such as {{$guid}} for uuids, instead of static sample values`)
+	fs.Var(dynamicVariableMap, "dynamic-variable", `A Postman dynamic variable used for a format or a type, as format={{$variable}}. May be repeated.`)
+	fs.StringVar(&fixturesDir, "fixtures-dir", "", `The directory holding the files to upload on file parameters`)
+	fs.BoolVar(&expandContentTypes, "expand-content-types", false, `Build a request per consumed and produced media type`)
+	fs.StringVar(&indent, "indent", "", `The string used to indent the collection. Default is two spaces.`)
+	fs.BoolVar(&compact, "compact", false, `Write the collection without any indentation nor whitespace`)
+	fs.BoolVar(&sortKeys, "sort-keys", false, `Sort the keys of the collection alphabetically`)
+	fs.StringVar(&swagSpecFilepath, "f", "swagger.json", `The swagger file to convert, or - for stdin`)
+	fs.StringVar(&pmanSpecFilepath, "o", "postman_collection.json", `The postman collection file as output, or - for stdout`)
+	fs.BoolVar(&failOnWarning, "fail-on-warning", false, `Exit with an error, without writing the collection, when the conversion is lossy`)
+	fs.BoolVar(&allowHooks, "allow-hooks", false, `Run the hooks of the default config file. Hooks of a config file given with -config are always run.`)
+	fs.Var((*stringList)(&filter.Include.Tags), "include-tag", `Only convert the operations with this tag. May be repeated.`)
+	fs.Var((*stringList)(&filter.Exclude.Tags), "exclude-tag", `Do not convert the operations with this tag. May be repeated.`)
+	fs.Var((*stringList)(&filter.Include.Paths), "include-path", `Only convert the paths matching this glob, such as /users/**. May be repeated.`)
+	fs.Var((*stringList)(&filter.Exclude.Paths), "exclude-path", `Do not convert the paths matching this glob, such as /internal/**. May be repeated.`)
+	fs.Var((*stringList)(&filter.Include.Methods), "include-method", `Only convert the operations using this HTTP method. May be repeated.`)
+	fs.Var((*stringList)(&filter.Exclude.Methods), "exclude-method", `Do not convert the operations using this HTTP method. May be repeated.`)
+	fs.Var((*stringList)(&filter.Include.OperationIDs), "include-operation", `Only convert the operation with this operationId. May be repeated.`)
+	fs.Var((*stringList)(&filter.Exclude.OperationIDs), "exclude-operation", `Do not convert the operation with this operationId. May be repeated.`)
+	fs.Var(keyValueMap(filter.Include.Extensions), "include-extension", `Only convert the operations defining this vendor extension, as key or key=value. May be repeated.`)
+	fs.Var(keyValueMap(filter.Exclude.Extensions), "exclude-extension", `Do not convert the operations defining this vendor extension, as key or key=value, such as x-internal=true. May be repeated.`)
+	fs.BoolVar(&filter.ExcludeDeprecated, "exclude-deprecated", false, `Do not convert the deprecated operations`)
+}
+
+//converterConfig builds the config of the converter from the options
+func converterConfig() postmanify.Config {
+
+	postmanHeaders := map[string]postman2.Header{}
+	if !noDefaultAuth {
+		postmanHeaders[defaultAuthorization.Key] = defaultAuthorization
+	}
+	for key, header := range headers {
+		postmanHeaders[key] = header
+	}
+
+	mode := postmanify.BodyMode(bodyMode.value)
+	if bodyMode.value == "auto" {
+		mode = postmanify.BodyModeAuto
+	}
+
+	//custom dynamic variables are added to the default ones, when enabled
+	var variables map[string]string
+	if dynamicVariables || len(dynamicVariableMap) > 0 {
+		variables = map[string]string{}
+		if dynamicVariables {
+			for key, value := range postmanify.DefaultDynamicVariables {
+				variables[key] = value
+			}
+		}
+		for key, value := range dynamicVariableMap {
+			variables[key] = value
+		}
+	}
+
+	return postmanify.Config{
+		Hostname:           host,
+		Schema:             scheme,
+		BasePath:           basePath,
+		PostmanHeaders:     postmanHeaders,
+		MaxDepth:           maxDepth,
+		RealisticData:      realisticData,
+		Seed:               seed,
+		DynamicVariables:   variables,
+		FixturesDir:        fixturesDir,
+		ExpandContentTypes: expandContentTypes,
+		Encoding: postmanify.EncodeOptions{
+			Indent:   indent,
+			Compact:  compact,
+			SortKeys: sortKeys,
+		},
+		Filter:       filter,
+		GroupBy:      postmanify.GroupBy(groupBy.value),
+		Naming:       postmanify.Naming(naming.value),
+		NameTemplate: nameTemplate,
+		Auth:         auth,
+		BodyMode:     mode,
+
+		CollectionTransformers: hooks,
+	}
+}
+
 func main() {
 
-	flag.StringVar(&host, "host", "", `The hostname for the API`)
-	flag.StringVar(&swagSpecFilepath, "f", "swagger.json", `The swagger file to convert`)
-	flag.StringVar(&pmanSpecFilepath, "o", "postman_collection.json", `The postman collection file as output`)
+	registerFlags(flag.CommandLine)
 	flag.Parse()
 
-	conv := postmanify.NewConverter(postmanify.Config{
-		PostmanHeaders: map[string]postman2.Header{
-			"Authorization": {
-				Key:   "Authorization",
-				Value: "Bearer {{my_access_token}}"},
-		},
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
 	})
 
-	swag, _ := ioutil.ReadFile(swagSpecFilepath)
-	postman, err := conv.Convert(swag)
+	file, err := loadConfig(configFile, set["config"])
+	if err != nil {
+		exit(exitInput, "unable to read the config file: %s", err)
+	}
+	if file != nil {
+		if err := file.apply(set); err != nil {
+			exit(exitInput, "invalid config file %s: %s", configFile, err)
+		}
+	}
+
+	conv := postmanify.NewConverter(converterConfig())
+
+	swag, err := readInput(swagSpecFilepath)
 	if err != nil {
-		panic(err)
+		exit(exitInput, "unable to read the swagger file: %s", err)
+	}
+
+	postman, report, err := conv.Convert(swag)
+	if err != nil {
+		exitOnConversionError(err)
+	}
+
+	for _, diagnostic := range report.Diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+	}
+
+	if failOnWarning && report.HasWarnings() {
+		for _, diagnostic := range report.Diagnostics {
+			var refErr *postmanify.RefResolutionError
+			if errors.As(diagnostic.Err, &refErr) {
+				exit(exitInvalidSpec, "%s, the collection is not written", refErr)
+			}
+		}
+		exit(exitWarnings, "the conversion is lossy, the collection is not written")
+	}
+
+	if err := writeOutput(pmanSpecFilepath, postman); err != nil {
+		exit(exitOutput, "unable to write the postman collection: %s", err)
+	}
+
+	if err := writeEnvironments(); err != nil {
+		exit(exitOutput, "unable to write the postman environments: %s", err)
 	}
 
-	if err := ioutil.WriteFile(pmanSpecFilepath, postman, 0644); err != nil {
-		panic(err)
+}
+
+//readInput reads a file, or stdin for -
+func readInput(path string) ([]byte, error) {
+	if path == stdio {
+		return ioutil.ReadAll(os.Stdin)
 	}
+	return ioutil.ReadFile(path)
+}
+
+//writeOutput writes a file, or stdout for -
+func writeOutput(path string, b []byte) error {
+	if path == stdio {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+//exitOnConversionError exits with a message and an exit code matching a conversion error
+func exitOnConversionError(err error) {
+
+	var schemaErr *postmanify.SchemaError
+
+	switch {
+	case errors.Is(err, postmanify.ErrUnsupportedVersion):
+		exit(exitUnsupportedVersion, "%s, only swagger 2.0 specs are supported", err)
+	case errors.As(err, &schemaErr):
+		exit(exitInvalidSpec, "the swagger file is not a valid spec: %s", schemaErr.Err)
+	}
+
+	exit(exitError, "unable to convert the swagger file: %s", err)
+}
 
+//exit prints an error message and exits with the given code
+func exit(code int, format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "postmanify: "+format+"\n", args...)
+	os.Exit(code)
 }
@@ -0,0 +1,141 @@
+package postmanify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/seblegall/postmanify/postman2"
+)
+
+const (
+	defaultIndent = "  "
+)
+
+//EncodeOptions defines how a postman collection is encoded as json
+type EncodeOptions struct {
+	//Indent is the string used to indent the json. Default is two spaces.
+	Indent string
+	//Compact encodes the json without any indentation nor whitespace. Indent is then ignored.
+	Compact bool
+	//SortKeys sorts the keys of every json object alphabetically, instead of following the postman schema order.
+	SortKeys bool
+}
+
+//EncodeCollection encodes a postman collection as json
+func EncodeCollection(pman *postman2.Collection, opts EncodeOptions) ([]byte, error) {
+
+	var buf bytes.Buffer
+	if err := WriteCollection(context.Background(), &buf, pman, opts); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+//WriteCollection streams a postman collection as json to a writer.
+//Folders are encoded one by one, so that the whole json is never held in memory.
+//Writing stops as soon as the context is done.
+func WriteCollection(ctx context.Context, w io.Writer, pman *postman2.Collection, opts EncodeOptions) error {
+
+	indent := opts.Indent
+	if indent == "" {
+		indent = defaultIndent
+	}
+	if opts.Compact {
+		indent = ""
+	}
+
+	newline := func(depth int) string {
+		if opts.Compact {
+			return ""
+		}
+		return "\n" + strings.Repeat(indent, depth)
+	}
+
+	separator := ": "
+	if opts.Compact {
+		separator = ":"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	info, err := encodeElement(pman.Info, opts, strings.Repeat(indent, 1), indent)
+	if err != nil {
+		return err
+	}
+
+	bw.WriteString("{" + newline(1) + `"info"` + separator)
+	bw.Write(info)
+	bw.WriteString("," + newline(1) + `"item"` + separator)
+
+	switch {
+	case pman.Item == nil:
+		bw.WriteString("null")
+	case len(pman.Item) == 0:
+		bw.WriteString("[]")
+	default:
+		bw.WriteString("[")
+		for i, folder := range pman.Item {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			item, err := encodeElement(folder, opts, strings.Repeat(indent, 2), indent)
+			if err != nil {
+				return err
+			}
+
+			if i > 0 {
+				bw.WriteString(",")
+			}
+			bw.WriteString(newline(2))
+			bw.Write(item)
+		}
+		bw.WriteString(newline(1) + "]")
+	}
+
+	bw.WriteString(newline(0) + "}")
+
+	return bw.Flush()
+}
+
+//encodeElement encodes a part of a collection as json, indented as a nested element
+func encodeElement(v interface{}, opts EncodeOptions, prefix, indent string) ([]byte, error) {
+
+	if opts.SortKeys {
+		sorted, err := sortKeys(v)
+		if err != nil {
+			return nil, err
+		}
+		v = sorted
+	}
+
+	if opts.Compact {
+		return json.Marshal(v)
+	}
+
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+//sortKeys converts a value into generic json maps, which are encoded with sorted keys
+func sortKeys(v interface{}) (interface{}, error) {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var sorted interface{}
+	if err := dec.Decode(&sorted); err != nil {
+		return nil, err
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,85 @@
+package postmanify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seblegall/postmanify/postman2"
+)
+
+func TestEncodeCollection(t *testing.T) {
+
+	pman := postman2.NewCollection("title", "description")
+	pman.AddItem(postman2.APIItem{Name: "item", Request: postman2.Request{Method: "GET"}}, "folder")
+
+	dataset := []struct {
+		input    EncodeOptions
+		expected string
+	}{
+		{
+			input:    EncodeOptions{Compact: true},
+			expected: `{"info":{"name":"title","description":"description","schema":"` + postman2.Schema + `"},"item":[{"name":"folder","item":[{"name":"item","request":{"url":{"auth":null},"method":"GET","body":{}}}]}]}`,
+		},
+		{
+			input:    EncodeOptions{Compact: true, SortKeys: true},
+			expected: `{"info":{"description":"description","name":"title","schema":"` + postman2.Schema + `"},"item":[{"item":[{"name":"item","request":{"body":{},"method":"GET","url":{"auth":null}}}],"name":"folder"}]}`,
+		},
+		{
+			input:    EncodeOptions{Indent: "\t", SortKeys: true},
+			expected: "{\n\t\"info\": {\n\t\t\"description\": \"description\",\n\t\t\"name\": \"title\",\n\t\t\"schema\": \"" + postman2.Schema + "\"\n\t},\n\t\"item\": [\n\t\t{\n\t\t\t\"item\": [\n\t\t\t\t{\n\t\t\t\t\t\"name\": \"item\",\n\t\t\t\t\t\"request\": {\n\t\t\t\t\t\t\"body\": {},\n\t\t\t\t\t\t\"method\": \"GET\",\n\t\t\t\t\t\t\"url\": {\n\t\t\t\t\t\t\t\"auth\": null\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t],\n\t\t\t\"name\": \"folder\"\n\t\t}\n\t]\n}",
+		},
+	}
+
+	for _, data := range dataset {
+		out, err := EncodeCollection(&pman, data.input)
+		assert.NoError(t, err)
+		assert.Equal(t, data.expected, string(out))
+	}
+
+	//default encoding is indented with two spaces
+	out, err := EncodeCollection(&pman, EncodeOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "{\n  \"info\": {\n    \"name\": \"title\",")
+}
+
+func TestWriteCollection(t *testing.T) {
+
+	pman := postman2.NewCollection("title", "description")
+
+	//a collection without any item
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCollection(context.Background(), &buf, &pman, EncodeOptions{}))
+	expected, _ := json.MarshalIndent(pman, "", "  ")
+	assert.Equal(t, string(expected), buf.String())
+
+	pman.AddItem(postman2.APIItem{Name: "first", Request: postman2.Request{Method: "GET"}}, "a")
+	pman.AddItem(postman2.APIItem{Name: "second", Request: postman2.Request{Method: "POST"}}, "b")
+
+	dataset := []struct {
+		input  EncodeOptions
+		indent string
+	}{
+		{input: EncodeOptions{}, indent: "  "},
+		{input: EncodeOptions{Indent: "\t"}, indent: "\t"},
+	}
+
+	for _, data := range dataset {
+		buf.Reset()
+		assert.NoError(t, WriteCollection(context.Background(), &buf, &pman, data.input))
+		expected, _ := json.MarshalIndent(pman, "", data.indent)
+		assert.Equal(t, string(expected), buf.String())
+	}
+
+	buf.Reset()
+	assert.NoError(t, WriteCollection(context.Background(), &buf, &pman, EncodeOptions{Compact: true}))
+	expected, _ = json.Marshal(pman)
+	assert.Equal(t, string(expected), buf.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, WriteCollection(ctx, &buf, &pman, EncodeOptions{}))
+}
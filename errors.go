@@ -0,0 +1,42 @@
+package postmanify
+
+import (
+	"errors"
+	"fmt"
+)
+
+//ErrUnsupportedVersion is returned when the spec is not a swagger 2.0 spec, such as an OpenAPI 3 one
+var ErrUnsupportedVersion = errors.New("unsupported specification version")
+
+//RefResolutionError is used when a reference of the swagger spec can not be resolved.
+//It does not stop the conversion : it is only exposed as the Err of a warning Diagnostic.
+type RefResolutionError struct {
+	//Ref is the unresolved reference, such as #/definitions/User
+	Ref string
+	//Location is the JSON pointer to the part of the swagger spec holding the reference
+	Location string
+}
+
+func (e *RefResolutionError) Error() string {
+	if e.Location == "" {
+		return fmt.Sprintf("unable to resolve reference %s", e.Ref)
+	}
+	return fmt.Sprintf("unable to resolve reference %s at %s", e.Ref, e.Location)
+}
+
+//SchemaError is used when a part of the swagger spec is invalid, or can not be converted
+type SchemaError struct {
+	//Location is the JSON pointer to the invalid part of the swagger spec, # for the whole spec
+	Location string
+	//Err is the underlying error
+	Err error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("invalid schema at %s: %s", e.Location, e.Err)
+}
+
+//Unwrap returns the underlying error
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
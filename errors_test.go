@@ -0,0 +1,68 @@
+package postmanify
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertErrors(t *testing.T) {
+
+	dataset := []struct {
+		input    string
+		version  bool
+		location string
+	}{
+		{input: `{"openapi": "3.0.0", "info": {"title": "v3", "version": "1.0"}, "paths": {}}`, version: true},
+		{input: `{"swagger": "1.2"}`, version: true},
+		{input: `{"info": {"title": "none", "version": "1.0"}}`, version: true},
+		{input: `{"swagger": "2.0", "paths": `, location: "#"},
+		{input: `{"swagger": "2.0", "paths": []}`, location: "#"},
+	}
+
+	for _, data := range dataset {
+		_, report, err := NewConverter(Config{}).Convert([]byte(data.input))
+		assert.Nil(t, report)
+		assert.Equal(t, data.version, errors.Is(err, ErrUnsupportedVersion), data.input)
+
+		var schemaErr *SchemaError
+		assert.Equal(t, data.location != "", errors.As(err, &schemaErr), data.input)
+		if data.location != "" {
+			assert.Equal(t, data.location, schemaErr.Location)
+			assert.NotNil(t, errors.Unwrap(schemaErr))
+		}
+	}
+}
+
+func TestRefResolutionError(t *testing.T) {
+
+	swag := `{
+		"swagger": "2.0",
+		"info": {"title": "refs", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"post": {
+					"tags": ["users"],
+					"parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Missing"}}]
+				}
+			}
+		}
+	}`
+
+	_, report, err := NewConverter(Config{}).Convert([]byte(swag))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, report.Diagnostics)
+
+	var refErr *RefResolutionError
+	assert.True(t, errors.As(report.Diagnostics[0].Err, &refErr))
+	assert.Equal(t, "#/definitions/Missing", refErr.Ref)
+	assert.Equal(t, "#/paths/~1users/post/parameters/0/schema", refErr.Location)
+	assert.Equal(t, "unable to resolve reference #/definitions/Missing at #/paths/~1users/post/parameters/0/schema", refErr.Error())
+
+	//the typed error is not part of the json report
+	b, err := json.Marshal(report.Diagnostics[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "Err")
+}
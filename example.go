@@ -0,0 +1,66 @@
+package postmanify
+
+import (
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	examplesExtension = "x-examples"
+)
+
+//namedExample represents a named request payload, documented with a "x-examples" swagger extension
+type namedExample struct {
+	Name    string
+	Summary string
+	Value   interface{}
+}
+
+//buildNamedExamples reads named request payloads from a "x-examples" swagger extension,
+//defined either on the operation or on its body parameter.
+//The extension follows the OpenAPI 3 examples map : each entry is an object holding a summary and a value.
+//An entry without any value is taken as the payload itself.
+//Examples are sorted by name.
+func buildNamedExamples(operation *spec.Operation) []namedExample {
+
+	examples := readNamedExamples(operation.Extensions)
+
+	for _, param := range operation.Parameters {
+		if param.In == "body" {
+			examples = append(examples, readNamedExamples(param.Extensions)...)
+		}
+	}
+
+	sort.SliceStable(examples, func(i, j int) bool {
+		return examples[i].Name < examples[j].Name
+	})
+
+	return examples
+}
+
+//readNamedExamples reads the "x-examples" map from swagger extensions
+func readNamedExamples(extensions spec.Extensions) []namedExample {
+
+	raw, ok := extensions[examplesExtension].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var examples []namedExample
+
+	for name, entry := range raw {
+		example := namedExample{Name: name, Value: entry}
+
+		if fields, ok := entry.(map[string]interface{}); ok {
+			if value, ok := fields["value"]; ok {
+				example.Value = value
+				example.Summary, _ = fields["summary"].(string)
+			}
+		}
+
+		examples = append(examples, example)
+	}
+
+	return examples
+}
@@ -0,0 +1,68 @@
+package postmanify
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildNamedExamples(t *testing.T) {
+
+	dataset := []struct {
+		input    *spec.Operation
+		expected []namedExample
+	}{
+		{
+			input:    &spec.Operation{},
+			expected: nil,
+		},
+		{
+			input: &spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						"x-examples": map[string]interface{}{
+							"valid_user": map[string]interface{}{
+								"summary": "A valid user",
+								"value":   map[string]interface{}{"name": "john"},
+							},
+							"admin_user": map[string]interface{}{"name": "admin", "admin": true},
+						},
+					},
+				},
+			},
+			expected: []namedExample{
+				{Name: "admin_user", Value: map[string]interface{}{"name": "admin", "admin": true}},
+				{Name: "valid_user", Summary: "A valid user", Value: map[string]interface{}{"name": "john"}},
+			},
+		},
+		{
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Parameters: []spec.Parameter{
+						{
+							ParamProps: spec.ParamProps{In: "body", Name: "body"},
+							VendorExtensible: spec.VendorExtensible{
+								Extensions: spec.Extensions{
+									"x-examples": map[string]interface{}{
+										"minimal_user": map[string]interface{}{
+											"summary": "A minimal user",
+											"value":   map[string]interface{}{},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []namedExample{
+				{Name: "minimal_user", Summary: "A minimal user", Value: map[string]interface{}{}},
+			},
+		},
+	}
+
+	for _, data := range dataset {
+		assert.Equal(t, data.expected, buildNamedExamples(data.input))
+	}
+}
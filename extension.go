@@ -0,0 +1,153 @@
+package postmanify
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/go-openapi/spec"
+)
+
+//Vendor extensions overriding the generated requests.
+//They may be defined at the root of the spec, on a path or on an operation : the nearest one is used.
+const (
+	postmanNameExtension        = "x-postman-name"
+	postmanFolderExtension      = "x-postman-folder"
+	postmanSkipExtension        = "x-postman-skip"
+	postmanHeadersExtension     = "x-postman-headers"
+	postmanBodyExtension        = "x-postman-body"
+	postmanAuthExtension        = "x-postman-auth"
+	postmanDescriptionExtension = "x-postman-description"
+)
+
+//postmanExtension reads a vendor extension of an operation, inherited from its path, then from the root of the spec
+func (c *Converter) postmanExtension(operation *spec.Operation, key string) (interface{}, bool) {
+	for _, extensions := range []spec.Extensions{operation.Extensions, c.pathExtensions, c.rootExtensions} {
+		if value, ok := extensions[key]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+//postmanString reads a string vendor extension of an operation, with inheritance
+func (c *Converter) postmanString(operation *spec.Operation, key string) string {
+
+	value, ok := c.postmanExtension(operation, key)
+	if !ok {
+		return ""
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		c.report(SeverityWarning, CodeInvalidValue, c.location, "%s must be a string and is ignored", key)
+		return ""
+	}
+
+	return strings.TrimSpace(s)
+}
+
+//skipped checks if an operation is flagged with a "x-postman-skip" extension, with inheritance
+func (c *Converter) skipped(operation *spec.Operation) bool {
+
+	value, ok := c.postmanExtension(operation, postmanSkipExtension)
+	if !ok {
+		return false
+	}
+
+	skip, ok := value.(bool)
+	if !ok {
+		c.report(SeverityWarning, CodeInvalidValue, c.location, "%s must be a boolean and is ignored", postmanSkipExtension)
+	}
+
+	return skip
+}
+
+//folderName returns the folder of an operation : its "x-postman-folder" extension, with inheritance, or its group
+func (c *Converter) folderName(url string, operation *spec.Operation) string {
+	if folder := c.postmanString(operation, postmanFolderExtension); folder != "" {
+		return folder
+	}
+	return c.groupName(url, operation)
+}
+
+//postmanHeaders reads the headers of a "x-postman-headers" extension, as a map of header names to values.
+//Headers defined at the root of the spec, on the path and on the operation are merged, the nearest one winning.
+func (c *Converter) postmanHeaders(operation *spec.Operation) []postman2.Header {
+
+	var headers []postman2.Header
+
+	for _, extensions := range []spec.Extensions{c.rootExtensions, c.pathExtensions, operation.Extensions} {
+		value, ok := extensions[postmanHeadersExtension]
+		if !ok {
+			continue
+		}
+
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			c.report(SeverityWarning, CodeInvalidValue, c.location, "%s must be an object and is ignored", postmanHeadersExtension)
+			continue
+		}
+
+		keys := []string{}
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			headers = append(headers, postman2.Header{Key: key, Value: fmt.Sprint(plainValue(fields[key]))})
+		}
+	}
+
+	return headers
+}
+
+//postmanBody reads a literal request body from a "x-postman-body" extension, with inheritance.
+//A string is used as is, other values are encoded according to the consumed media type.
+func (c *Converter) postmanBody(operation *spec.Operation) (string, bool) {
+
+	value, ok := c.postmanExtension(operation, postmanBodyExtension)
+	if !ok {
+		return "", false
+	}
+
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+
+	raw, err := c.serializeBody(consumedRawMediaType(operation.Consumes), c.bodyDefinition(operation), bodySchema(operation), value)
+	if err != nil {
+		c.reportError(SeverityError, CodeEncodingFailed, c.location, &SchemaError{Location: c.location, Err: err}, "unable to encode %s: %s", postmanBodyExtension, err)
+		return "", false
+	}
+
+	return raw, true
+}
+
+//postmanAuth reads the Postman auth of a request from a "x-postman-auth" extension, with inheritance, or the Auth
+//defined in the config.
+//The extension follows the Postman auth format, such as {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]}.
+func (c *Converter) postmanAuth(operation *spec.Operation) *postman2.Auth {
+
+	value, ok := c.postmanExtension(operation, postmanAuthExtension)
+	if !ok {
+		return c.config.Auth
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		c.report(SeverityWarning, CodeInvalidValue, c.location, "%s is invalid and is ignored: %s", postmanAuthExtension, err)
+		return nil
+	}
+
+	var auth postman2.Auth
+	if err := json.Unmarshal(b, &auth); err != nil || auth.Type == "" {
+		c.report(SeverityWarning, CodeInvalidValue, c.location, "%s must be a Postman auth object and is ignored", postmanAuthExtension)
+		return nil
+	}
+
+	return &auth
+}
@@ -0,0 +1,181 @@
+package postmanify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertPostmanExtensions(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "extensions", "version": "1.0"},
+		"consumes": ["application/json"],
+		"x-postman-headers": {"X-Api-Version": "1", "X-Client": "root"},
+		"x-postman-auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+		"paths": {
+			"/users": {
+				"x-postman-headers": {"X-Client": "path"},
+				"x-postman-folder": "Users",
+				"get": {
+					"tags": ["users"],
+					"x-postman-name": "List users",
+					"x-postman-description": "Lists every user",
+					"x-postman-auth": {"type": "noauth"}
+				},
+				"post": {
+					"tags": ["users"],
+					"x-postman-headers": {"X-Client": "operation"},
+					"x-postman-body": {"name": "{{name}}"},
+					"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}]
+				},
+				"delete": {
+					"tags": ["users"],
+					"x-postman-skip": true
+				}
+			},
+			"/internal": {
+				"x-postman-skip": true,
+				"get": {"tags": ["internal"]},
+				"post": {"tags": ["internal"], "x-postman-skip": false}
+			},
+			"/untagged": {
+				"get": {"x-postman-folder": "Misc", "x-postman-body": "ping"}
+			}
+		}
+	}`)
+
+	out, report, err := NewConverter(Config{}).Convert(swag)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Diagnostics)
+
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(out, &collection))
+
+	assert.Len(t, collection.Item, 3)
+
+	internal := collection.Item[0]
+	assert.Equal(t, "internal", internal.Name)
+	assert.Len(t, internal.Item, 1)
+	assert.Equal(t, "POST", internal.Item[0].Request.Method)
+
+	misc := collection.Item[1]
+	assert.Equal(t, "Misc", misc.Name)
+	assert.Equal(t, "/untagged", misc.Item[0].Name)
+	assert.Equal(t, "raw", misc.Item[0].Request.Body.Mode)
+	assert.Equal(t, "ping", misc.Item[0].Request.Body.Raw)
+
+	users := collection.Item[2]
+	assert.Equal(t, "Users", users.Name)
+	assert.Len(t, users.Item, 2)
+
+	list := users.Item[0]
+	assert.Equal(t, "List users", list.Name)
+	assert.Equal(t, "Lists every user", list.Request.Description)
+	assert.Equal(t, &postman2.Auth{Type: "noauth"}, list.Request.Auth)
+	assert.Equal(t, []postman2.Header{
+		{Key: "X-Api-Version", Value: "1"},
+		{Key: "X-Client", Value: "path"},
+	}, list.Request.Header)
+
+	create := users.Item[1]
+	assert.Equal(t, "/users", create.Name)
+	assert.Equal(t, &postman2.Auth{Type: "bearer", Attributes: []postman2.AuthAttribute{{Key: "token", Value: "{{token}}", Type: "string"}}}, create.Request.Auth)
+	assert.Equal(t, indentJSON(`{"name":"{{name}}"}`), create.Request.Body.Raw)
+	assert.Equal(t, []postman2.Header{
+		{Key: "Content-Type", Value: "application/json"},
+		{Key: "X-Api-Version", Value: "1"},
+		{Key: "X-Client", Value: "operation"},
+	}, create.Request.Header)
+}
+
+func TestConvertInvalidPostmanExtensions(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "extensions", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"tags": ["users"],
+					"x-postman-skip": "yes",
+					"x-postman-name": 42,
+					"x-postman-headers": ["X-Client"],
+					"x-postman-auth": "bearer"
+				}
+			}
+		}
+	}`)
+
+	_, report, err := NewConverter(Config{}).Convert(swag)
+	assert.NoError(t, err)
+
+	var messages []string
+	for _, d := range report.Diagnostics {
+		assert.Equal(t, CodeInvalidValue, d.Code)
+		assert.Equal(t, "#/paths/~1users/get", d.Location)
+		messages = append(messages, d.Message)
+	}
+	assert.Equal(t, []string{
+		"x-postman-skip must be a boolean and is ignored",
+		"x-postman-headers must be an object and is ignored",
+		"x-postman-auth must be a Postman auth object and is ignored",
+		"x-postman-name must be a string and is ignored",
+	}, messages)
+}
+
+func TestConvertConfigAuth(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "auth", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"get": {"tags": ["users"]},
+				"post": {"tags": ["users"], "x-postman-auth": {"type": "noauth"}}
+			}
+		}
+	}`)
+
+	auth := &postman2.Auth{Type: "apikey", Attributes: []postman2.AuthAttribute{{Key: "key", Value: "X-Api-Key"}, {Key: "value", Value: "{{api_key}}"}}}
+
+	pman, _, err := NewConverter(Config{Auth: auth}).Convert(swag)
+	assert.NoError(t, err)
+
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(pman, &collection))
+
+	assert.Equal(t, auth, collection.Item[0].Item[0].Request.Auth)
+	assert.Equal(t, &postman2.Auth{Type: "noauth"}, collection.Item[0].Item[1].Request.Auth)
+}
+
+func TestConvertPostmanBodyNamedExamples(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "extensions", "version": "1.0"},
+		"paths": {
+			"/a": {
+				"post": {
+					"tags": ["a"],
+					"x-postman-body": {"literal": true},
+					"x-examples": {"one": {"value": {"x": 1}}},
+					"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"x": {"type": "integer"}}}}]
+				}
+			}
+		}
+	}`)
+
+	pman, _, err := NewConverter(Config{}).Convert(swag)
+	assert.NoError(t, err)
+
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(pman, &collection))
+
+	assert.Len(t, collection.Item[0].Item, 1)
+	assert.Equal(t, "/a", collection.Item[0].Item[0].Name)
+	assert.Equal(t, indentJSON(`{"literal":true}`), collection.Item[0].Item[0].Request.Body.Raw)
+}
@@ -0,0 +1,154 @@
+package postmanify
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+//OperationFilter selects the operations converted into the collection.
+//An operation is converted when it matches the Include selector, and does not match the Exclude one.
+type OperationFilter struct {
+	//Include selects the operations to convert. An operation must match every criterion defined,
+	//and at least one value of each. An empty selector includes every operation.
+	Include OperationSelector
+	//Exclude selects the operations not to convert. An operation matching any value of any criterion is excluded.
+	Exclude OperationSelector
+	//ExcludeDeprecated excludes the operations flagged as deprecated.
+	ExcludeDeprecated bool
+}
+
+//OperationSelector lists criteria matching swagger operations
+type OperationSelector struct {
+	//Tags matches the operations having one of the tags
+	Tags []string
+	//Paths matches the operations of the paths matching one of the globs. * matches a single path segment,
+	//and ** any number of segments, such as /internal/**.
+	Paths []string
+	//Methods matches the operations using one of the HTTP methods, whatever their case
+	Methods []string
+	//OperationIDs matches the operations having one of the operationIds
+	OperationIDs []string
+	//Extensions matches the operations defining one of the vendor extensions, such as x-internal.
+	//When a value is defined, the extension must also have this value, such as "true".
+	Extensions map[string]string
+}
+
+//isEmpty checks if a selector defines no criterion
+func (s OperationSelector) isEmpty() bool {
+	return len(s.Tags) == 0 && len(s.Paths) == 0 && len(s.Methods) == 0 && len(s.OperationIDs) == 0 && len(s.Extensions) == 0
+}
+
+//selected checks if an operation is selected by the filter defined in the config
+func (c *Converter) selected(url, method string, operation *spec.Operation) bool {
+
+	filter := c.config.Filter
+
+	if filter.ExcludeDeprecated && operation.Deprecated {
+		return false
+	}
+
+	include := filter.Include
+	if !include.isEmpty() {
+		if (len(include.Tags) > 0 && !matchTags(include.Tags, operation)) ||
+			(len(include.Paths) > 0 && !matchPaths(include.Paths, url)) ||
+			(len(include.Methods) > 0 && !matchMethods(include.Methods, method)) ||
+			(len(include.OperationIDs) > 0 && !matchOperationIDs(include.OperationIDs, operation)) ||
+			(len(include.Extensions) > 0 && !matchExtensions(include.Extensions, operation)) {
+			return false
+		}
+	}
+
+	exclude := filter.Exclude
+	if matchTags(exclude.Tags, operation) ||
+		matchPaths(exclude.Paths, url) ||
+		matchMethods(exclude.Methods, method) ||
+		matchOperationIDs(exclude.OperationIDs, operation) ||
+		matchExtensions(exclude.Extensions, operation) {
+		return false
+	}
+
+	return true
+}
+
+//matchTags checks if an operation has one of the tags
+func matchTags(tags []string, operation *spec.Operation) bool {
+	for _, tag := range tags {
+		for _, opTag := range operation.Tags {
+			if strings.TrimSpace(tag) == strings.TrimSpace(opTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//matchPaths checks if a path matches one of the globs
+func matchPaths(globs []string, url string) bool {
+	for _, glob := range globs {
+		if matchGlob(strings.Split(strings.Trim(glob, "/"), "/"), strings.Split(strings.Trim(url, "/"), "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+//matchGlob matches path segments against glob segments. ** matches any number of segments.
+func matchGlob(glob, segments []string) bool {
+
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+
+	if glob[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(glob[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, err := path.Match(glob[0], segments[0]); err != nil || !ok {
+		return false
+	}
+
+	return matchGlob(glob[1:], segments[1:])
+}
+
+//matchMethods checks if a method is one of the methods
+func matchMethods(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(strings.TrimSpace(m), method) {
+			return true
+		}
+	}
+	return false
+}
+
+//matchOperationIDs checks if an operation has one of the operationIds
+func matchOperationIDs(ids []string, operation *spec.Operation) bool {
+	for _, id := range ids {
+		if operation.ID != "" && strings.TrimSpace(id) == operation.ID {
+			return true
+		}
+	}
+	return false
+}
+
+//matchExtensions checks if an operation defines one of the extensions, with the expected value if any
+func matchExtensions(extensions map[string]string, operation *spec.Operation) bool {
+	for key, expected := range extensions {
+		value, ok := operation.Extensions[strings.ToLower(key)]
+		if ok && (expected == "" || fmt.Sprint(value) == expected) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package postmanify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPaths(t *testing.T) {
+
+	dataset := []struct {
+		glob     string
+		url      string
+		expected bool
+	}{
+		{glob: "/internal/**", url: "/internal", expected: true},
+		{glob: "/internal/**", url: "/internal/users/{id}", expected: true},
+		{glob: "/internal/**", url: "/users/internal", expected: false},
+		{glob: "/users/*", url: "/users/{id}", expected: true},
+		{glob: "/users/*", url: "/users/{id}/groups", expected: false},
+		{glob: "/**/groups", url: "/users/{id}/groups", expected: true},
+		{glob: "/users", url: "/users/", expected: true},
+		{glob: "/admin*", url: "/admins", expected: true},
+	}
+
+	for _, data := range dataset {
+		assert.Equal(t, data.expected, matchPaths([]string{data.glob}, data.url), data.glob+" "+data.url)
+	}
+}
+
+func TestConvertFilter(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "filter", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"get": {"tags": ["users"], "operationId": "listUsers"},
+				"post": {"tags": ["users"], "operationId": "createUser", "x-internal": true},
+				"head": {"tags": ["users"]}
+			},
+			"/users/{id}": {
+				"delete": {"tags": ["users", "admin"], "operationId": "deleteUser"}
+			},
+			"/internal/health": {
+				"get": {"tags": ["internal"], "operationId": "health"}
+			},
+			"/legacy": {
+				"get": {"tags": ["legacy"], "operationId": "legacy", "deprecated": true}
+			}
+		}
+	}`)
+
+	dataset := []struct {
+		filter   OperationFilter
+		expected []string
+		head     bool
+	}{
+		{
+			filter:   OperationFilter{},
+			expected: []string{"GET /internal/health", "GET /legacy", "GET /users", "POST /users", "DELETE /users/{id}"},
+			head:     true,
+		},
+		{
+			filter:   OperationFilter{Include: OperationSelector{Tags: []string{"users"}, Methods: []string{"get", "delete"}}},
+			expected: []string{"GET /users", "DELETE /users/{id}"},
+		},
+		{
+			filter: OperationFilter{
+				Exclude:           OperationSelector{Tags: []string{"admin"}, Paths: []string{"/internal/**"}, Extensions: map[string]string{"x-internal": "true"}},
+				ExcludeDeprecated: true,
+			},
+			expected: []string{"GET /users"},
+			head:     true,
+		},
+		{
+			filter:   OperationFilter{Include: OperationSelector{OperationIDs: []string{"health", "legacy"}}},
+			expected: []string{"GET /internal/health", "GET /legacy"},
+		},
+		{
+			filter:   OperationFilter{Include: OperationSelector{Extensions: map[string]string{"x-internal": ""}}},
+			expected: []string{"POST /users"},
+		},
+	}
+
+	for _, data := range dataset {
+		out, report, err := NewConverter(Config{Filter: data.filter}).Convert(swag)
+		assert.NoError(t, err)
+
+		var collection postman2.Collection
+		assert.NoError(t, json.Unmarshal(out, &collection))
+
+		var requests []string
+		for _, folder := range collection.Item {
+			for _, item := range folder.Item {
+				requests = append(requests, item.Request.Method+" "+item.Name)
+			}
+		}
+		assert.Equal(t, data.expected, requests)
+
+		//unsupported operations are only reported when selected
+		var head bool
+		for _, d := range report.Diagnostics {
+			head = head || d.Code == CodeUnsupportedOperation
+		}
+		assert.Equal(t, data.head, head)
+	}
+}
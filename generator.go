@@ -0,0 +1,284 @@
+package postmanify
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+//ValueGenerator generates sample values for properties and parameters defining no example, default or enum value.
+//Generate is called with the property or parameter name, its swagger type and its format.
+type ValueGenerator interface {
+	Generate(name, propType, format string) interface{}
+}
+
+//ValueGeneratorFunc is a function used as a ValueGenerator
+type ValueGeneratorFunc func(name, propType, format string) interface{}
+
+//Generate calls f(name, propType, format)
+func (f ValueGeneratorFunc) Generate(name, propType, format string) interface{} {
+	return f(name, propType, format)
+}
+
+//newValueGenerator creates the value generator matching the converter configuration
+func newValueGenerator(cfg Config) ValueGenerator {
+	if cfg.ValueGenerator != nil {
+		return cfg.ValueGenerator
+	}
+	if cfg.RealisticData {
+		return fakerGenerator{seed: cfg.Seed}
+	}
+	return staticGenerator{}
+}
+
+const (
+	postmanValueExtension = "x-postman-value"
+)
+
+//DefaultDynamicVariables maps common formats and types to Postman dynamic variables.
+//It may be used as Config.DynamicVariables.
+var DefaultDynamicVariables = map[string]string{
+	"uuid":      "{{$guid}}",
+	"email":     "{{$randomEmail}}",
+	"date-time": "{{$isoTimestamp}}",
+	"int32":     "{{$randomInt}}",
+	"int64":     "{{$randomInt}}",
+	"integer":   "{{$randomInt}}",
+	"boolean":   "{{$randomBoolean}}",
+}
+
+//dynamicVariable is a Postman variable, such as {{$randomInt}}, used as a non-string value.
+//It is rendered unquoted in json bodies.
+type dynamicVariable string
+
+//generateValue generates a sample value for a property or a parameter.
+//Postman dynamic variables defined in the config take precedence over generated values.
+func (c *Converter) generateValue(name, propType, format string) interface{} {
+	if variable, ok := c.dynamicVariable(propType, format); ok {
+		return variable
+	}
+	return c.generator.Generate(name, propType, format)
+}
+
+//dynamicVariable returns the Postman dynamic variable configured for a format or, as a fallback, for a type
+func (c *Converter) dynamicVariable(propType, format string) (interface{}, bool) {
+
+	variable, ok := c.config.DynamicVariables[format]
+	if !ok || format == "" {
+		variable, ok = c.config.DynamicVariables[propType]
+	}
+	if !ok {
+		return nil, false
+	}
+
+	return typedVariable(variable, propType), true
+}
+
+//postmanValue reads a literal value overriding any generated one from a "x-postman-value" swagger extension
+func postmanValue(extensions spec.Extensions, propType string) (interface{}, bool) {
+
+	value, ok := extensions[postmanValueExtension]
+	if !ok {
+		return nil, false
+	}
+
+	if s, ok := value.(string); ok {
+		return typedVariable(s, propType), true
+	}
+
+	return value, true
+}
+
+//plainValue returns dynamic variables as plain strings, for values which are not rendered in json bodies
+func plainValue(v interface{}) interface{} {
+	if variable, ok := v.(dynamicVariable); ok {
+		return string(variable)
+	}
+	return v
+}
+
+//typedVariable returns a Postman variable as a dynamicVariable for non-string types
+func typedVariable(variable, propType string) interface{} {
+	if propType == "" || propType == "string" {
+		return variable
+	}
+	return dynamicVariable(variable)
+}
+
+//generateString generates a sample string value for a parameter, such as a header or a form field
+func (c *Converter) generateString(name, propType, format string) string {
+	if propType == "" {
+		propType = "string"
+	}
+	return fmt.Sprint(plainValue(c.generateValue(name, propType, format)))
+}
+
+//staticGenerator generates constant sample values : 0 for integers, "string" for strings and a fixed date for date-times.
+type staticGenerator struct{}
+
+//Generate generates a constant sample value
+func (staticGenerator) Generate(name, propType, format string) interface{} {
+
+	//Property has no example value : we set one by default
+	if propType == "integer" {
+		return 0
+	}
+
+	if propType == "string" {
+		switch format {
+		case "date-time":
+			return time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC).Format(time.RFC3339)
+		default:
+			return "string"
+		}
+	}
+
+	return ""
+}
+
+var (
+	fakeFirstNames = []string{"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "Emma", "Lucas", "Chloe", "Hugo", "Alice", "Louis", "Sofia", "Noah"}
+	fakeLastNames  = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Martin", "Bernard", "Dubois", "Moreau", "Lefebvre", "Rossi", "Muller", "Schmidt"}
+	fakeCountries  = []string{"FR", "US", "GB", "DE", "ES", "IT", "NL", "BE", "CH", "CA", "JP", "BR", "AU", "SE", "PT", "IE"}
+	fakeCities     = []string{"Paris", "London", "Berlin", "Madrid", "Rome", "Amsterdam", "Brussels", "Geneva", "Montreal", "Tokyo", "Lisbon", "Dublin", "Lyon", "Munich", "Milan", "Boston"}
+	fakeStreets    = []string{"Main Street", "High Street", "Park Avenue", "Oak Street", "Rue de la Paix", "Church Road", "Mill Lane", "Station Road"}
+	fakeCompanies  = []string{"Acme Corp", "Globex", "Initech", "Umbrella", "Hooli", "Stark Industries", "Wayne Enterprises", "Soylent"}
+	fakeWords      = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "tempor", "magna", "aliqua"}
+)
+
+//fakerGenerator generates realistic sample values from property names and formats.
+//Each value is generated from the generator seed and the property name, type and format : the output is reproducible,
+//and adding a property to a schema does not change the values generated for the other ones.
+type fakerGenerator struct {
+	seed int64
+}
+
+//Generate generates a realistic sample value
+func (g fakerGenerator) Generate(name, propType, format string) interface{} {
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join([]string{name, propType, format}, "|")))
+	rng := rand.New(rand.NewSource(g.seed ^ int64(h.Sum64())))
+
+	key := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(name))
+
+	if propType == "string" || propType == "" {
+		if value, ok := fakeFormattedString(rng, name, key, format); ok {
+			return value
+		}
+	}
+
+	switch propType {
+	case "integer":
+		if key == "age" {
+			return 18 + rng.Intn(70)
+		}
+		return rng.Intn(1000)
+	case "number":
+		return float64(rng.Intn(100000)) / 100
+	case "boolean":
+		return rng.Intn(2) == 1
+	case "string":
+		return pick(rng, fakeWords)
+	}
+
+	return ""
+}
+
+//fakeFormattedString generates a realistic string from a property format, then from its name
+func fakeFormattedString(rng *rand.Rand, name, key, format string) (string, bool) {
+
+	first, last := pick(rng, fakeFirstNames), pick(rng, fakeLastNames)
+
+	switch format {
+	case "email":
+		return strings.ToLower(first + "." + last + "@example.com"), true
+	case "uuid":
+		return fakeUUID(rng), true
+	case "date-time":
+		return fakeTime(rng).Format(time.RFC3339), true
+	case "date":
+		return fakeTime(rng).Format("2006-01-02"), true
+	case "uri", "url":
+		return "https://www.example.com/" + pick(rng, fakeWords), true
+	case "hostname":
+		return pick(rng, fakeWords) + ".example.com", true
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+rng.Intn(254)), true
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", 1+rng.Intn(0xffff)), true
+	case "password":
+		return fakePassword(rng), true
+	}
+
+	switch {
+	case strings.Contains(key, "email"):
+		return strings.ToLower(first + "." + last + "@example.com"), true
+	case key == "uuid" || key == "guid":
+		return fakeUUID(rng), true
+	case strings.Contains(key, "password"):
+		return fakePassword(rng), true
+	case strings.Contains(key, "firstname") || strings.Contains(key, "givenname"):
+		return first, true
+	case strings.Contains(key, "lastname") || strings.Contains(key, "familyname") || strings.Contains(key, "surname"):
+		return last, true
+	case strings.Contains(key, "username") || key == "login":
+		return strings.ToLower(first) + fmt.Sprintf("%02d", rng.Intn(100)), true
+	case strings.Contains(key, "fullname") || key == "name":
+		return first + " " + last, true
+	case strings.Contains(key, "country"):
+		return pick(rng, fakeCountries), true
+	case strings.Contains(key, "city"):
+		return pick(rng, fakeCities), true
+	case strings.Contains(key, "phone") || strings.Contains(key, "mobile"):
+		return fmt.Sprintf("+1-555-01%02d", rng.Intn(100)), true
+	case strings.Contains(key, "zip") || strings.Contains(key, "postalcode") || strings.Contains(key, "postcode"):
+		return fmt.Sprintf("%05d", rng.Intn(100000)), true
+	case strings.Contains(key, "street") || strings.Contains(key, "address"):
+		return fmt.Sprintf("%d %s", 1+rng.Intn(200), pick(rng, fakeStreets)), true
+	case strings.Contains(key, "company") || strings.Contains(key, "organization"):
+		return pick(rng, fakeCompanies), true
+	case strings.HasSuffix(key, "url") || strings.Contains(key, "website"):
+		return "https://www.example.com/" + pick(rng, fakeWords), true
+	case strings.HasSuffix(key, "date") || strings.HasSuffix(key, "birthday"):
+		return fakeTime(rng).Format("2006-01-02"), true
+	case strings.HasSuffix(name, "At") || strings.HasSuffix(strings.ToLower(name), "_at"):
+		return fakeTime(rng).Format(time.RFC3339), true
+	}
+
+	return "", false
+}
+
+//pick returns a random value from a list
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
+
+//fakeUUID generates a random version 4 uuid
+func fakeUUID(rng *rand.Rand) string {
+	b := make([]byte, 16)
+	rng.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+//fakeTime generates a random time between 2000 and 2025
+func fakeTime(rng *rand.Rand) time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(rng.Int63n(int64(25*365*24*time.Hour)))).Truncate(time.Second)
+}
+
+//fakePassword generates a random alphanumeric password
+func fakePassword(rng *rand.Rand) string {
+	const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, 12)
+	for i := range b {
+		b[i] = chars[rng.Intn(len(chars))]
+	}
+	return string(b)
+}
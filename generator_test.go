@@ -0,0 +1,132 @@
+package postmanify
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seblegall/postmanify/postman2"
+)
+
+func TestStaticGenerator(t *testing.T) {
+
+	dataset := []struct {
+		input    []string
+		expected interface{}
+	}{
+		{input: []string{"id", "integer", ""}, expected: 0},
+		{input: []string{"username", "string", ""}, expected: "string"},
+		{input: []string{"createdAt", "string", "date-time"}, expected: "2009-11-17T20:34:58Z"},
+		{input: []string{"enabled", "boolean", ""}, expected: ""},
+	}
+
+	for _, data := range dataset {
+		assert.Equal(t, data.expected, staticGenerator{}.Generate(data.input[0], data.input[1], data.input[2]))
+	}
+}
+
+func TestFakerGenerator(t *testing.T) {
+
+	gen := fakerGenerator{seed: 42}
+
+	dataset := []struct {
+		input    []string
+		expected *regexp.Regexp
+	}{
+		{input: []string{"contact", "string", "email"}, expected: regexp.MustCompile(`^[a-z]+\.[a-z]+@example\.com$`)},
+		{input: []string{"email_address", "string", ""}, expected: regexp.MustCompile(`^[a-z]+\.[a-z]+@example\.com$`)},
+		{input: []string{"id", "string", "uuid"}, expected: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{input: []string{"first_name", "string", ""}, expected: regexp.MustCompile(`^[A-Z][a-z]+$`)},
+		{input: []string{"country", "string", ""}, expected: regexp.MustCompile(`^[A-Z]{2}$`)},
+		{input: []string{"createdAt", "string", ""}, expected: regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)},
+		{input: []string{"birth_date", "string", "date"}, expected: regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)},
+	}
+
+	for _, data := range dataset {
+		value := gen.Generate(data.input[0], data.input[1], data.input[2])
+		assert.Regexp(t, data.expected, value)
+		//a given seed always generates the same value
+		assert.Equal(t, value, fakerGenerator{seed: 42}.Generate(data.input[0], data.input[1], data.input[2]))
+	}
+
+	assert.IsType(t, 0, gen.Generate("count", "integer", ""))
+	assert.IsType(t, true, gen.Generate("enabled", "boolean", ""))
+	assert.IsType(t, 0.0, gen.Generate("price", "number", ""))
+}
+
+func TestBuildPropertiesRealisticData(t *testing.T) {
+
+	properties := map[string]spec.Schema{
+		"email":   {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+		"country": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+	}
+
+	conv := NewConverter(Config{RealisticData: true, Seed: 1})
+	body := conv.buildProperties(properties)
+
+	assert.Equal(t, body, NewConverter(Config{RealisticData: true, Seed: 1}).buildProperties(properties))
+
+	//adding a property does not change the values generated for the other ones
+	properties["city"] = spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}}
+
+	var before, after map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(body), &before))
+	assert.NoError(t, json.Unmarshal([]byte(conv.buildProperties(properties)), &after))
+	assert.Equal(t, before["email"], after["email"])
+	assert.Equal(t, before["country"], after["country"])
+}
+
+func TestDynamicVariables(t *testing.T) {
+
+	conv := NewConverter(Config{DynamicVariables: DefaultDynamicVariables})
+
+	properties := map[string]spec.Schema{
+		"id":        {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Format: "uuid"}},
+		"count":     {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}, Format: "int32"}},
+		"createdAt": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Format: "date-time"}},
+		"username":  {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+		"userId": {
+			SchemaProps:      spec.SchemaProps{Type: spec.StringOrArray{"integer"}},
+			VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-postman-value": "{{userId}}"}},
+		},
+	}
+
+	assert.Equal(t, "{\n\t\"count\": {{$randomInt}},\n\t\"createdAt\": \"{{$isoTimestamp}}\",\n\t\"id\": \"{{$guid}}\",\n\t\"userId\": {{userId}},\n\t\"username\": \"string\"\n}", conv.buildProperties(properties))
+
+	operation := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Parameters: []spec.Parameter{
+				{
+					ParamProps:   spec.ParamProps{In: "query", Name: "page"},
+					SimpleSchema: spec.SimpleSchema{Type: "integer"},
+				},
+				{
+					ParamProps:       spec.ParamProps{In: "query", Name: "tenant"},
+					SimpleSchema:     spec.SimpleSchema{Type: "string"},
+					VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-postman-value": "{{tenant}}"}},
+				},
+				{
+					ParamProps:   spec.ParamProps{In: "header", Name: "X-Request-Id"},
+					SimpleSchema: spec.SimpleSchema{Type: "string", Format: "uuid"},
+				},
+				{
+					ParamProps:   spec.ParamProps{In: "formData", Name: "email"},
+					SimpleSchema: spec.SimpleSchema{Type: "string", Format: "email"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []postman2.URLQueryParam{
+		{Key: "page", Value: "{{$randomInt}}", Disabled: true},
+		{Key: "tenant", Value: "{{tenant}}", Disabled: true},
+	}, conv.buildQueryParams(operation))
+
+	conv.config.PostmanHeaders = map[string]postman2.Header{}
+	assert.Equal(t, []postman2.Header{{Key: "X-Request-Id", Value: "{{$guid}}", Disabled: true}}, conv.buildPostmanHeaders(operation))
+
+	assert.Equal(t, "{{$randomEmail}}", conv.buildPostmanBody(operation).FormData[0].Value)
+}
@@ -0,0 +1,89 @@
+package postmanify
+
+import (
+	"strings"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/go-openapi/spec"
+)
+
+//ItemTransformer customizes each request of the collection, with the swagger operation it was generated from.
+//Returning an error stops the conversion.
+type ItemTransformer interface {
+	TransformItem(item *postman2.APIItem, path, method string, operation *spec.Operation) error
+}
+
+//ItemTransformerFunc is a function used as an ItemTransformer
+type ItemTransformerFunc func(item *postman2.APIItem, path, method string, operation *spec.Operation) error
+
+//TransformItem calls f(item, path, method, operation)
+func (f ItemTransformerFunc) TransformItem(item *postman2.APIItem, path, method string, operation *spec.Operation) error {
+	return f(item, path, method, operation)
+}
+
+//FolderTransformer customizes each folder of the collection, with the swagger tag it was generated from.
+//The tag only holds a name when it is not documented at the root of the spec.
+//Returning an error stops the conversion.
+type FolderTransformer interface {
+	TransformFolder(folder *postman2.FolderItem, tag spec.Tag) error
+}
+
+//FolderTransformerFunc is a function used as a FolderTransformer
+type FolderTransformerFunc func(folder *postman2.FolderItem, tag spec.Tag) error
+
+//TransformFolder calls f(folder, tag)
+func (f FolderTransformerFunc) TransformFolder(folder *postman2.FolderItem, tag spec.Tag) error {
+	return f(folder, tag)
+}
+
+//CollectionTransformer customizes the whole collection, with the expanded swagger spec it was generated from.
+//Returning an error stops the conversion.
+type CollectionTransformer interface {
+	TransformCollection(collection *postman2.Collection, swagger *spec.Swagger) error
+}
+
+//CollectionTransformerFunc is a function used as a CollectionTransformer
+type CollectionTransformerFunc func(collection *postman2.Collection, swagger *spec.Swagger) error
+
+//TransformCollection calls f(collection, swagger)
+func (f CollectionTransformerFunc) TransformCollection(collection *postman2.Collection, swagger *spec.Swagger) error {
+	return f(collection, swagger)
+}
+
+//transformItem applies the item transformers defined in the config, in order
+func (c *Converter) transformItem(item *postman2.APIItem, path, method string, operation *spec.Operation) error {
+	for _, transformer := range c.config.ItemTransformers {
+		if err := transformer.TransformItem(item, path, strings.ToUpper(method), operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//transformCollection applies the folder transformers, then the collection transformers defined in the config, in order
+func (c *Converter) transformCollection(pman *postman2.Collection, swag *spec.Swagger) error {
+
+	for i := range pman.Item {
+		tag := spec.Tag{TagProps: spec.TagProps{Name: pman.Item[i].Name}}
+		for _, t := range swag.Tags {
+			if strings.TrimSpace(t.Name) == pman.Item[i].Name {
+				tag = t
+				break
+			}
+		}
+
+		for _, transformer := range c.config.FolderTransformers {
+			if err := transformer.TransformFolder(&pman.Item[i], tag); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, transformer := range c.config.CollectionTransformers {
+		if err := transformer.TransformCollection(pman, swag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
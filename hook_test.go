@@ -0,0 +1,145 @@
+package postmanify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertHooks(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "hooks", "version": "1.0"},
+		"tags": [{"name": "users", "description": "Users management"}],
+		"paths": {
+			"/users": {
+				"get": {"tags": ["users"], "operationId": "listUsers"},
+				"post": {
+					"tags": ["users"],
+					"operationId": "createUser",
+					"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}]
+				}
+			},
+			"/groups": {
+				"get": {"tags": ["groups"], "operationId": "listGroups"}
+			}
+		}
+	}`)
+
+	conv := NewConverter(Config{
+		ValueGenerator: ValueGeneratorFunc(func(name, propType, format string) interface{} {
+			return strings.ToUpper(name)
+		}),
+		ItemTransformers: []ItemTransformer{
+			ItemTransformerFunc(func(item *postman2.APIItem, path, method string, operation *spec.Operation) error {
+				item.Name = operation.ID
+				if method == "POST" {
+					item.Request.Header = append(item.Request.Header, postman2.Header{Key: "X-Path", Value: path})
+				}
+				return nil
+			}),
+		},
+		FolderTransformers: []FolderTransformer{
+			FolderTransformerFunc(func(folder *postman2.FolderItem, tag spec.Tag) error {
+				folder.Description = tag.Description
+				return nil
+			}),
+		},
+		CollectionTransformers: []CollectionTransformer{
+			CollectionTransformerFunc(func(collection *postman2.Collection, swagger *spec.Swagger) error {
+				collection.Info.Name = swagger.Info.Title + " " + swagger.Info.Version
+				return nil
+			}),
+		},
+	})
+
+	collection, _, err := conv.ConvertToCollection(context.Background(), swag)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "hooks 1.0", collection.Info.Name)
+
+	assert.Equal(t, "groups", collection.Item[0].Name)
+	assert.Equal(t, "", collection.Item[0].Description)
+	assert.Equal(t, "listGroups", collection.Item[0].Item[0].Name)
+
+	assert.Equal(t, "users", collection.Item[1].Name)
+	assert.Equal(t, "Users management", collection.Item[1].Description)
+	assert.Equal(t, "listUsers", collection.Item[1].Item[0].Name)
+	assert.Equal(t, "createUser", collection.Item[1].Item[1].Name)
+	assert.Equal(t, []postman2.Header{{Key: "X-Path", Value: "/users"}}, collection.Item[1].Item[1].Request.Header)
+	assert.Equal(t, indentJSON(`{"name":"NAME"}`), collection.Item[1].Item[1].Request.Body.Raw)
+}
+
+func TestConvertHooksError(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "hooks", "version": "1.0"},
+		"paths": {"/users": {"get": {"tags": ["users"]}}}
+	}`)
+
+	errHook := errors.New("hook failure")
+
+	dataset := []Config{
+		{ItemTransformers: []ItemTransformer{ItemTransformerFunc(func(*postman2.APIItem, string, string, *spec.Operation) error { return errHook })}},
+		{FolderTransformers: []FolderTransformer{FolderTransformerFunc(func(*postman2.FolderItem, spec.Tag) error { return errHook })}},
+		{CollectionTransformers: []CollectionTransformer{CollectionTransformerFunc(func(*postman2.Collection, *spec.Swagger) error { return errHook })}},
+	}
+
+	for _, cfg := range dataset {
+		out, report, err := NewConverter(cfg).Convert(swag)
+		assert.Nil(t, out)
+		assert.Nil(t, report)
+		assert.Equal(t, errHook, err)
+	}
+}
+
+func TestConvertHooksNamedExamples(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "hooks", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"post": {
+					"tags": ["users"],
+					"x-examples": {
+						"admin": {"value": {"name": "admin"}},
+						"guest": {"value": {"name": "guest"}}
+					},
+					"parameters": [
+						{"in": "query", "name": "page", "type": "integer", "required": true},
+						{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}
+					]
+				}
+			}
+		}
+	}`)
+
+	conv := NewConverter(Config{
+		PostmanHeaders: map[string]postman2.Header{"Authorization": {Key: "Authorization", Value: "x"}},
+		ItemTransformers: []ItemTransformer{
+			ItemTransformerFunc(func(item *postman2.APIItem, path, method string, operation *spec.Operation) error {
+				item.Request.Header[0].Value += "!"
+				item.Request.URL.Query[0].Value = item.Name
+				return nil
+			}),
+		},
+	})
+
+	collection, _, err := conv.ConvertToCollection(context.Background(), swag)
+	assert.NoError(t, err)
+
+	items := collection.Item[0].Item
+	assert.Len(t, items, 2)
+	for _, item := range items {
+		assert.Equal(t, "x!", item.Request.Header[0].Value)
+		assert.Equal(t, item.Name, item.Request.URL.Query[0].Value)
+	}
+}
@@ -0,0 +1,122 @@
+package postmanify
+
+import (
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+//GroupBy defines how requests are grouped into folders
+type GroupBy string
+
+//Folder groupings
+const (
+	//GroupByTag groups requests by the first tag of their operation. Untagged operations are not converted.
+	GroupByTag GroupBy = "tag"
+	//GroupByPath groups requests by the first segment of their path, such as users for /users/{id}.
+	GroupByPath GroupBy = "path"
+)
+
+//Naming defines how requests are named
+type Naming string
+
+//Request namings
+const (
+	//NameByPath names requests after their path
+	NameByPath Naming = "path"
+	//NameBySummary names requests after the summary of their operation, or their path when it has none
+	NameBySummary Naming = "summary"
+	//NameByOperationID names requests after the operationId of their operation, or their path when it has none
+	NameByOperationID Naming = "operationId"
+)
+
+//BodyMode defines the postman mode of request bodies
+type BodyMode string
+
+//Body modes
+const (
+	//BodyModeAuto chooses the mode from the media types consumed by the operation
+	BodyModeAuto BodyMode = ""
+	//BodyModeRaw sends body schemas as raw bodies, even for operations consuming forms
+	BodyModeRaw BodyMode = "raw"
+	//BodyModeURLEncoded sends body schemas and form parameters as x-www-form-urlencoded
+	BodyModeURLEncoded BodyMode = "urlencoded"
+	//BodyModeFormData sends body schemas and form parameters as multipart/form-data
+	BodyModeFormData BodyMode = "formdata"
+)
+
+//groupName returns the folder of an operation following the GroupBy config, without any "x-postman-folder" extension
+func (c *Converter) groupName(url string, operation *spec.Operation) string {
+
+	if c.config.GroupBy == GroupByPath {
+		for _, segment := range strings.Split(url, "/") {
+			if segment = strings.TrimSpace(segment); segment != "" {
+				return segment
+			}
+		}
+		return "/"
+	}
+
+	if len(operation.Tags) > 0 {
+		return strings.TrimSpace(operation.Tags[0])
+	}
+
+	return ""
+}
+
+//requestName returns the name of an operation following the NameTemplate or Naming config, without any
+//"x-postman-name" extension. A request is named after its path when its name is empty.
+func (c *Converter) requestName(url, method string, operation *spec.Operation) string {
+
+	if c.config.NameTemplate != "" {
+		var tag string
+		if len(operation.Tags) > 0 {
+			tag = operation.Tags[0]
+		}
+		name := strings.NewReplacer(
+			"{method}", strings.ToUpper(method),
+			"{path}", url,
+			"{summary}", operation.Summary,
+			"{operationId}", operation.ID,
+			"{tag}", tag,
+		).Replace(c.config.NameTemplate)
+		if name = strings.Join(strings.Fields(name), " "); name != "" {
+			return name
+		}
+		return url
+	}
+
+	switch c.config.Naming {
+	case NameBySummary:
+		if summary := strings.TrimSpace(operation.Summary); summary != "" {
+			return summary
+		}
+	case NameByOperationID:
+		if id := strings.TrimSpace(operation.ID); id != "" {
+			return id
+		}
+	}
+
+	return url
+}
+
+//formMediaType returns the form media type of an operation body following the BodyMode config : the consumed one
+//by default, none for raw bodies, unless the operation has form parameters which can only be sent as a form.
+func (c *Converter) formMediaType(operation *spec.Operation) string {
+
+	switch c.config.BodyMode {
+	case BodyModeURLEncoded:
+		return mediaTypeURLEncoded
+	case BodyModeFormData:
+		return mediaTypeMultipart
+	case BodyModeRaw:
+		for _, param := range operation.Parameters {
+			if param.In == "formData" {
+				return consumedFormMediaType(operation.Consumes)
+			}
+		}
+		return ""
+	}
+
+	return consumedFormMediaType(operation.Consumes)
+}
@@ -0,0 +1,117 @@
+package postmanify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertOptions(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "options", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"post": {
+					"operationId": "createUser",
+					"summary": "Create a user",
+					"consumes": ["application/json"],
+					"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string", "example": "john"}}}}]
+				}
+			},
+			"/users/{id}": {
+				"get": {
+					"tags": ["users"],
+					"summary": "Get a user",
+					"parameters": [{"in": "path", "name": "id", "type": "string", "required": true}]
+				}
+			},
+			"/": {
+				"get": {"operationId": "root"}
+			}
+		}
+	}`)
+
+	dataset := []struct {
+		cfg      Config
+		expected map[string][]string
+	}{
+		{
+			cfg:      Config{},
+			expected: map[string][]string{"users": {"/users/{id}"}},
+		},
+		{
+			cfg:      Config{GroupBy: GroupByPath, Naming: NameBySummary},
+			expected: map[string][]string{"/": {"/"}, "users": {"Create a user", "Get a user"}},
+		},
+		{
+			cfg:      Config{GroupBy: GroupByPath, Naming: NameByOperationID},
+			expected: map[string][]string{"/": {"root"}, "users": {"createUser", "/users/{id}"}},
+		},
+		{
+			cfg:      Config{GroupBy: GroupByPath, NameTemplate: "{method} {operationId} {tag}"},
+			expected: map[string][]string{"/": {"GET root"}, "users": {"POST createUser", "GET users"}},
+		},
+	}
+
+	for _, data := range dataset {
+		pman, _, err := NewConverter(data.cfg).Convert(swag)
+		assert.NoError(t, err)
+
+		var collection postman2.Collection
+		assert.NoError(t, json.Unmarshal(pman, &collection))
+		names := map[string][]string{}
+		for _, folder := range collection.Item {
+			for _, item := range folder.Item {
+				names[folder.Name] = append(names[folder.Name], item.Name)
+			}
+		}
+		assert.Equal(t, data.expected, names)
+	}
+}
+
+func TestFormMediaType(t *testing.T) {
+
+	operation := func(s string) *spec.Operation {
+		var op spec.Operation
+		assert.NoError(t, json.Unmarshal([]byte(s), &op))
+		return &op
+	}
+
+	jsonBody := operation(`{"consumes": ["application/json"], "parameters": [{"in": "body", "name": "body", "schema": {"type": "object"}}]}`)
+	formBody := operation(`{"consumes": ["application/x-www-form-urlencoded"], "parameters": [{"in": "body", "name": "body", "schema": {"type": "object"}}]}`)
+	formParams := operation(`{"consumes": ["application/x-www-form-urlencoded"], "parameters": [{"in": "formData", "name": "name", "type": "string"}]}`)
+
+	dataset := []struct {
+		mode     BodyMode
+		expected []string
+	}{
+		{mode: BodyModeAuto, expected: []string{"", mediaTypeURLEncoded, mediaTypeURLEncoded}},
+		{mode: BodyModeRaw, expected: []string{"", "", mediaTypeURLEncoded}},
+		{mode: BodyModeURLEncoded, expected: []string{mediaTypeURLEncoded, mediaTypeURLEncoded, mediaTypeURLEncoded}},
+		{mode: BodyModeFormData, expected: []string{mediaTypeMultipart, mediaTypeMultipart, mediaTypeMultipart}},
+	}
+
+	for _, data := range dataset {
+		conv := NewConverter(Config{BodyMode: data.mode})
+		assert.Equal(t, data.expected, []string{conv.formMediaType(jsonBody), conv.formMediaType(formBody), conv.formMediaType(formParams)})
+	}
+}
+
+func TestBodyModeRaw(t *testing.T) {
+
+	var operation spec.Operation
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"consumes": ["application/x-www-form-urlencoded"],
+		"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string", "example": "john"}}}}]
+	}`), &operation))
+
+	body := NewConverter(Config{BodyMode: BodyModeRaw}).buildPostmanBody(&operation)
+
+	assert.Equal(t, "raw", body.Mode)
+	assert.Equal(t, postman2.RawOptions{Language: "json"}, body.Options.Raw)
+}
@@ -0,0 +1,41 @@
+package postman2
+
+import (
+	"sort"
+)
+
+//Environment represents a Postman environment : a set of variables, such as a baseUrl, imported next to a collection
+type Environment struct {
+	Name   string             `json:"name"`
+	Values []EnvironmentValue `json:"values"`
+	Scope  string             `json:"_postman_variable_scope"`
+}
+
+//EnvironmentValue represents a variable of a Postman environment
+type EnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+//NewEnvironment creates a Postman environment from variables, sorted by key
+func NewEnvironment(name string, variables map[string]string) Environment {
+
+	keys := []string{}
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := []EnvironmentValue{}
+	for _, key := range keys {
+		values = append(values, EnvironmentValue{Key: key, Value: variables[key], Type: "default", Enabled: true})
+	}
+
+	return Environment{
+		Name:   name,
+		Values: values,
+		Scope:  "environment",
+	}
+}
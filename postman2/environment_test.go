@@ -0,0 +1,28 @@
+package postman2_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seblegall/postmanify/postman2"
+)
+
+func TestNewEnvironment(t *testing.T) {
+
+	env := postman2.NewEnvironment("staging", map[string]string{"token": "", "baseUrl": "https://staging.example.com"})
+
+	b, err := json.Marshal(env)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "staging",
+		"values": [
+			{"key": "baseUrl", "value": "https://staging.example.com", "type": "default", "enabled": true},
+			{"key": "token", "value": "", "type": "default", "enabled": true}
+		],
+		"_postman_variable_scope": "environment"
+	}`, string(b))
+
+	assert.Equal(t, []postman2.EnvironmentValue{}, postman2.NewEnvironment("empty", nil).Values)
+}
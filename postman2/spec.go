@@ -1,8 +1,7 @@
 package postman2
 
 import (
-	"regexp"
-	"strings"
+	"encoding/json"
 )
 
 const (
@@ -12,8 +11,11 @@ const (
 
 //Collection represents a Postman Collection
 type Collection struct {
-	Info CollectionInfo `json:"info"`
-	Item []FolderItem   `json:"item"`
+	Info     CollectionInfo `json:"info"`
+	Item     []FolderItem   `json:"item"`
+	Event    []Event        `json:"event,omitempty"`
+	Variable []Variable     `json:"variable,omitempty"`
+	Auth     *Auth          `json:"auth,omitempty"`
 }
 
 //NewCollection creates a Postman Collection using the title and the description
@@ -53,9 +55,21 @@ type CollectionInfo struct {
 
 //FolderItem represents a Postman folder part of a collection
 type FolderItem struct {
-	Name        string    `json:"name,omitempty"`
-	Description string    `json:"description,omitempty"`
-	Item        []APIItem `json:"item,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Item        []APIItem  `json:"item,omitempty"`
+	Event       []Event    `json:"event,omitempty"`
+	Variable    []Variable `json:"variable,omitempty"`
+	Auth        *Auth      `json:"auth,omitempty"`
+}
+
+//Variable represents a Postman variable defined on a collection or a folder
+type Variable struct {
+	Key         string      `json:"key"`
+	Value       interface{} `json:"value,omitempty"`
+	Type        string      `json:"type,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Disabled    bool        `json:"disabled,omitempty"`
 }
 
 //APIItem represents a Postman request
@@ -83,16 +97,77 @@ type Request struct {
 	Method      string      `json:"method,omitempty"`
 	Header      []Header    `json:"header,omitempty"`
 	Body        RequestBody `json:"body,omitempty"`
+	Auth        *Auth       `json:"auth,omitempty"`
 	Description string      `json:"description,omitempty"`
 }
 
+//Auth represents the authentication of a Postman request, such as {"type": "bearer", "bearer": [...]}.
+//The attributes are encoded under the key named after the auth type.
+type Auth struct {
+	Type       string
+	Attributes []AuthAttribute
+}
+
+//AuthAttribute represents an attribute of a Postman auth, such as a bearer token
+type AuthAttribute struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value,omitempty"`
+	Type  string      `json:"type,omitempty"`
+}
+
+//MarshalJSON encodes the auth attributes under the key named after the auth type
+func (a Auth) MarshalJSON() ([]byte, error) {
+	if a.Type == "" || a.Type == "noauth" {
+		return json.Marshal(map[string]string{"type": "noauth"})
+	}
+
+	attributes := a.Attributes
+	if attributes == nil {
+		attributes = []AuthAttribute{}
+	}
+
+	typ, err := json.Marshal(a.Type)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(`{"type":` + string(typ) + `,` + string(typ) + `:` + string(attrs) + `}`), nil
+}
+
+//UnmarshalJSON decodes the auth attributes from the key named after the auth type
+func (a *Auth) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*a = Auth{}
+	if typ, ok := raw["type"]; ok {
+		if err := json.Unmarshal(typ, &a.Type); err != nil {
+			return err
+		}
+	}
+
+	if attrs, ok := raw[a.Type]; ok {
+		return json.Unmarshal(attrs, &a.Attributes)
+	}
+
+	return nil
+}
+
 //URL represents a Postman URL, part from the request
 type URL struct {
 	Raw      string            `json:"raw,omitempty"`
 	Protocol string            `json:"protocol,omitempty"`
 	Auth     map[string]string `json:"auth"`
 	Host     []string          `json:"host,omitempty"`
+	Port     string            `json:"port,omitempty"`
 	Path     []string          `json:"path,omitempty"`
+	Hash     string            `json:"hash,omitempty"`
 	Variable []URLVariable     `json:"variable,omitempty"`
 	Query    []URLQueryParam   `json:"query,omitempty"`
 }
@@ -103,31 +178,6 @@ type URLVariable struct {
 	ID    string      `json:"id,omitempty"`
 }
 
-//NewURL creates a Postman URL from a rowURL
-//It extracts the protocol, the host and the path
-func NewURL(rawURL string) URL {
-	rawURL = strings.TrimSpace(rawURL)
-	url := URL{Raw: rawURL, Variable: []URLVariable{}}
-	rx := regexp.MustCompile(`^([a-z]+)://([^/]+)/(.*)$`)
-	rs := rx.FindAllStringSubmatch(rawURL, -1)
-
-	if len(rs) > 0 {
-		for _, m := range rs {
-			url.Protocol = m[1]
-
-			hostname := m[2]
-			hostnameParts := strings.Split(hostname, ".")
-			url.Host = hostnameParts
-
-			path := m[3]
-			pathParts := strings.Split(path, "/")
-			url.Path = pathParts
-		}
-	}
-
-	return url
-}
-
 //AddVariable add a Postman URL variable to the URL
 func (url *URL) AddVariable(key string, value interface{}) {
 	variable := URLVariable{ID: key, Value: value}
@@ -139,17 +189,22 @@ func (url *URL) AddQueryParam(param URLQueryParam) {
 	url.Query = append(url.Query, param)
 }
 
-//URLQueryParam represents a Postman URL query param
+//URLQueryParam represents a Postman URL query param.
+//A disabled param is listed in the request, without being sent.
 type URLQueryParam struct {
-	Key   string      `json:"key,omitempty"`
-	Value interface{} `json:"value,omitempty"`
+	Key         string      `json:"key,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Disabled    bool        `json:"disabled,omitempty"`
 }
 
-//Header represents a header, part from the Postman request
+//Header represents a header, part from the Postman request.
+//A disabled header is listed in the request, without being sent.
 type Header struct {
 	Key         string `json:"key,omitempty"`
 	Value       string `json:"value,omitempty"`
 	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
 }
 
 //RequestBody represents the Postman request's body
@@ -158,20 +213,44 @@ type RequestBody struct {
 	URLEncoded []URLEncodedParam `json:"urlencoded,omitempty"`
 	FormData   []FormData        `json:"formdata,omitempty"`
 	Raw        string            `json:"raw,omitempty"`
+	File       *BodyFile         `json:"file,omitempty"`
+	Options    *BodyOptions      `json:"options,omitempty"`
 }
 
-//FormData represents the request body formatted as formdata
+//BodyFile represents a file sent as the request body
+type BodyFile struct {
+	Src string `json:"src,omitempty"`
+}
+
+//BodyOptions represents the Postman request's body options
+type BodyOptions struct {
+	Raw RawOptions `json:"raw"`
+}
+
+//RawOptions represents the options of a raw body, such as its language
+type RawOptions struct {
+	Language string `json:"language,omitempty"`
+}
+
+//FormData represents the request body formatted as formdata.
+//A disabled field is listed in the request, without being sent.
 type FormData struct {
-	Key     string `json:"key,omitempty"`
-	Value   string `json:"value,omitempty"`
-	Type    string `json:"type,omitempty"`
-	Enabled bool   `json:"enabled,omitempty"`
+	Key         string `json:"key,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Src         string `json:"src,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
 }
 
-//URLEncodedParam represents the request body formatted as URl encoded
+//URLEncodedParam represents the request body formatted as URl encoded.
+//A disabled field is listed in the request, without being sent.
 type URLEncodedParam struct {
-	Key     string `json:"key,omitempty"`
-	Value   string `json:"value,omitempty"`
-	Type    string `json:"type,omitempty"`
-	Enabled bool   `json:"enabled,omitempty"`
+	Key         string `json:"key,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
 }
@@ -1,6 +1,7 @@
 package postman2_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,7 +60,47 @@ func TestNewURL(t *testing.T) {
 				Raw:      "https://{{test}}{{test}}{{test}}/test?test=true",
 				Protocol: "https",
 				Host:     []string{"{{test}}{{test}}{{test}}"},
-				Path:     []string{"test?test=true"},
+				Path:     []string{"test"},
+				Query:    []postman2.URLQueryParam{{Key: "test", Value: "true"}},
+			},
+		},
+		{
+			input: "http://localhost:8080",
+			expected: postman2.URL{
+				Raw:      "http://localhost:8080",
+				Protocol: "http",
+				Host:     []string{"localhost"},
+				Port:     "8080",
+			},
+		},
+		{
+			input: "http://[::1]:8080/users/{{id}}",
+			expected: postman2.URL{
+				Raw:      "http://[::1]:8080/users/{{id}}",
+				Protocol: "http",
+				Host:     []string{"[::1]"},
+				Port:     "8080",
+				Path:     []string{"users", "{{id}}"},
+			},
+		},
+		{
+			input: "{{baseUrl}}/users/{{id}}.{{format}}?page={{page}}&flag#top",
+			expected: postman2.URL{
+				Raw:   "{{baseUrl}}/users/{{id}}.{{format}}?page={{page}}&flag#top",
+				Host:  []string{"{{baseUrl}}"},
+				Path:  []string{"users", "{{id}}.{{format}}"},
+				Query: []postman2.URLQueryParam{{Key: "page", Value: "{{page}}"}, {Key: "flag"}},
+				Hash:  "top",
+			},
+		},
+		{
+			input: "https://{{region}}.api.example.com:{{port}}/v1/",
+			expected: postman2.URL{
+				Raw:      "https://{{region}}.api.example.com:{{port}}/v1/",
+				Protocol: "https",
+				Host:     []string{"{{region}}", "api", "example", "com"},
+				Port:     "{{port}}",
+				Path:     []string{"v1", ""},
 			},
 		},
 	}
@@ -70,8 +111,11 @@ func TestNewURL(t *testing.T) {
 		assert.Equal(t, data.expected.Raw, url.Raw)
 		assert.Equal(t, data.expected.Protocol, url.Protocol)
 		assert.Equal(t, data.expected.Host, url.Host)
+		assert.Equal(t, data.expected.Port, url.Port)
 		assert.Equal(t, data.expected.Path, url.Path)
-
+		assert.Equal(t, data.expected.Query, url.Query)
+		assert.Equal(t, data.expected.Hash, url.Hash)
+		assert.Equal(t, data.input, url.String())
 	}
 }
 
@@ -93,3 +137,30 @@ func TestAddVariable(t *testing.T) {
 	assert.Equal(t, "test2", url.Variable[1].ID)
 	assert.Equal(t, "value2", url.Variable[1].Value)
 }
+
+func TestAuthJSON(t *testing.T) {
+
+	dataset := []struct {
+		input    postman2.Auth
+		expected string
+	}{
+		{
+			input:    postman2.Auth{Type: "bearer", Attributes: []postman2.AuthAttribute{{Key: "token", Value: "{{token}}", Type: "string"}}},
+			expected: `{"type":"bearer","bearer":[{"key":"token","value":"{{token}}","type":"string"}]}`,
+		},
+		{
+			input:    postman2.Auth{Type: "noauth"},
+			expected: `{"type":"noauth"}`,
+		},
+	}
+
+	for _, data := range dataset {
+		b, err := json.Marshal(data.input)
+		assert.NoError(t, err)
+		assert.Equal(t, data.expected, string(b))
+
+		var auth postman2.Auth
+		assert.NoError(t, json.Unmarshal(b, &auth))
+		assert.Equal(t, data.input, auth)
+	}
+}
@@ -0,0 +1,150 @@
+package postman2
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+//variablePattern matches Postman variables, such as {{host}}
+var variablePattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+//NewURL creates a Postman URL from a raw URL, such as http://{{host}}:8080/users/{{id}}?page=1#top.
+//It extracts the protocol, the host, the port, the path, the query and the hash.
+//The protocol is optional, and Postman variables may be used anywhere, including as the whole host.
+func NewURL(rawURL string) URL {
+
+	rawURL = strings.TrimSpace(rawURL)
+	postmanURL := URL{Raw: rawURL, Variable: []URLVariable{}}
+
+	if rawURL == "" {
+		return postmanURL
+	}
+
+	//variables are replaced with placeholders, valid anywhere in a url, and restored once the url is parsed
+	prefix := "postmanvariable"
+	for strings.Contains(rawURL, prefix) {
+		prefix += "x"
+	}
+
+	var variables []string
+	template := variablePattern.ReplaceAllStringFunc(rawURL, func(variable string) string {
+		variables = append(variables, variable)
+		return fmt.Sprintf("%s%d_", prefix, len(variables)-1)
+	})
+	restore := func(s string) string {
+		for i, variable := range variables {
+			s = strings.Replace(s, fmt.Sprintf("%s%d_", prefix, i), variable, -1)
+		}
+		return s
+	}
+
+	//the protocol is the part before the first :// , if the url starts with it
+	if i := strings.IndexAny(template, "/?#"); i > 1 && strings.HasPrefix(template[i-1:], "://") {
+		postmanURL.Protocol = restore(template[:i-1])
+		template = template[i+2:]
+	}
+
+	//the authority is split by hand, since the port may be a variable
+	authority := template
+	if i := strings.IndexAny(template, "/?#"); i >= 0 {
+		authority, template = template[:i], template[i:]
+	} else {
+		template = ""
+	}
+
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		authority = authority[i+1:]
+	}
+	authority = strings.TrimSpace(authority)
+
+	hostname := authority
+	if strings.HasPrefix(authority, "[") && strings.Contains(authority, "]") {
+		//ipv6 literal
+		i := strings.Index(authority, "]")
+		hostname = authority[:i+1]
+		postmanURL.Port = strings.TrimPrefix(authority[i+1:], ":")
+		postmanURL.Host = []string{restore(hostname)}
+	} else {
+		if i := strings.LastIndex(authority, ":"); i >= 0 && i < len(authority)-1 {
+			hostname, postmanURL.Port = strings.TrimSpace(authority[:i]), authority[i+1:]
+		}
+		if hostname != "" {
+			for _, part := range strings.Split(hostname, ".") {
+				postmanURL.Host = append(postmanURL.Host, restore(part))
+			}
+		}
+	}
+	postmanURL.Port = restore(strings.TrimSpace(postmanURL.Port))
+
+	//the authority is already parsed : a fake one prevents a path starting with // from being read as an authority
+	u, err := url.Parse("http://host" + template)
+	if err != nil {
+		//invalid escapes : the path is kept as is, without any query nor hash
+		u = &url.URL{RawPath: strings.SplitN(strings.SplitN(template, "#", 2)[0], "?", 2)[0]}
+		u.Path = u.RawPath
+	}
+
+	if path := strings.TrimPrefix(u.EscapedPath(), "/"); path != "" {
+		for _, segment := range strings.Split(path, "/") {
+			postmanURL.Path = append(postmanURL.Path, restore(segment))
+		}
+	}
+
+	if u.RawQuery != "" {
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			parts := strings.SplitN(pair, "=", 2)
+			param := URLQueryParam{Key: restore(parts[0])}
+			if len(parts) == 2 {
+				param.Value = restore(parts[1])
+			}
+			postmanURL.Query = append(postmanURL.Query, param)
+		}
+	}
+
+	postmanURL.Hash = restore(u.EscapedFragment())
+
+	return postmanURL
+}
+
+//String renders the URL from its parts, including its enabled query params
+func (url URL) String() string {
+
+	var b strings.Builder
+
+	if url.Protocol != "" {
+		b.WriteString(url.Protocol + "://")
+	}
+
+	b.WriteString(strings.Join(url.Host, "."))
+
+	if url.Port != "" {
+		b.WriteString(":" + url.Port)
+	}
+
+	if len(url.Path) > 0 {
+		b.WriteString("/" + strings.Join(url.Path, "/"))
+	}
+
+	var query []string
+	for _, param := range url.Query {
+		if param.Disabled {
+			continue
+		}
+		if param.Value == nil {
+			query = append(query, param.Key)
+			continue
+		}
+		query = append(query, param.Key+"="+fmt.Sprint(param.Value))
+	}
+	if len(query) > 0 {
+		b.WriteString("?" + strings.Join(query, "&"))
+	}
+
+	if url.Hash != "" {
+		b.WriteString("#" + url.Hash)
+	}
+
+	return b.String()
+}
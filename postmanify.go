@@ -1,7 +1,11 @@
 package postmanify
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"strings"
 
 	"github.com/seblegall/postmanify/postman2"
@@ -19,62 +23,257 @@ type Config struct {
 	BasePath       string
 	//PostmanHeaders represents a collection of header to add on each documented path before generating the corresponding postman collection.
 	PostmanHeaders map[string]postman2.Header
+	//MaxDepth is the maximum nesting depth of generated request bodies. Deeper schemas are rendered empty.
+	//Default is 10.
+	MaxDepth int
+	//RealisticData enables the generation of realistic sample values (names, emails, countries...) from property
+	//names and formats, instead of constant values such as "string".
+	RealisticData bool
+	//Seed is used to generate realistic sample values. A given seed always generates the same values.
+	Seed int64
+	//DynamicVariables maps formats (or types, for properties without any format) to Postman dynamic variables,
+	//such as {{$guid}}, used instead of static sample values. See DefaultDynamicVariables.
+	DynamicVariables map[string]string
+	//FixturesDir is the directory holding the files to upload on file parameters.
+	FixturesDir string
+	//ExpandContentTypes expands each operation consuming or producing several media types into a request per
+	//media type, each with its own Content-Type and Accept headers and body.
+	ExpandContentTypes bool
+	//Encoding defines how Convert encodes the postman collection as json. Default is indented with two spaces.
+	Encoding EncodeOptions
+	//Filter selects the operations converted into the collection. Default is every tagged operation.
+	Filter OperationFilter
+	//GroupBy defines the folder of each request. Default is GroupByTag.
+	GroupBy GroupBy
+	//Naming defines the name of each request. Default is NameByPath.
+	Naming Naming
+	//NameTemplate names each request after a template, such as "{method} {path}", instead of Naming.
+	//Placeholders are {method}, {path}, {summary}, {operationId} and {tag}.
+	NameTemplate string
+	//Auth is the auth of each request without any "x-postman-auth" extension, such as a bearer token
+	Auth *postman2.Auth
+	//BodyMode defines the mode of request bodies. Default is BodyModeAuto.
+	BodyMode BodyMode
+	//ValueGenerator generates sample values instead of the built-in generators. RealisticData and Seed are then ignored.
+	//The generator and transformers are shared by all conversions : they must be safe for concurrent use when the
+	//converter is.
+	ValueGenerator ValueGenerator
+	//ItemTransformers customize each generated request, in order.
+	ItemTransformers []ItemTransformer
+	//FolderTransformers customize each generated folder, in order, once all requests are generated.
+	FolderTransformers []FolderTransformer
+	//CollectionTransformers customize the generated collection, in order, once all folders are transformed.
+	CollectionTransformers []CollectionTransformer
 }
 
-//Converter represent a Swagger2.0 documentation to Postman 2.1 collections converter
+//Converter represent a Swagger2.0 documentation to Postman 2.1 collections converter.
+//A Converter may be reused for several specs, and is safe for concurrent use : each conversion
+//works on its own state.
 type Converter struct {
-	config Config
-}
+	config    Config
+	generator ValueGenerator
 
+	//conversion state, only set on the per-call converter created by Convert
+	definitions spec.Definitions
+	//bodyDefinitions are the definitions referenced by body parameters before the spec expansion, by JSON pointer
+	//to the parameter
+	bodyDefinitions map[string]string
+	consumes        []string
+	produces        []string
+	diagnostics     Report
+	//location is the JSON pointer to the operation being converted
+	location string
+	//parameterLocations are the JSON pointers to the parameters of the operation being converted,
+	//including the ones inherited from its path
+	parameterLocations []string
+	//rootExtensions and pathExtensions are the vendor extensions inherited by the operation being converted
+	rootExtensions spec.Extensions
+	pathExtensions spec.Extensions
+}
 
 //NewConverter creates a new converter
 func NewConverter(cfg Config) *Converter {
 	return &Converter{
-		config: cfg,
+		config:    cfg,
+		generator: newValueGenerator(cfg),
 	}
 }
 
 //Convert converts a swagger specification to a postman collection.
-//Convert expected a json input defined as a slice of byte, and returns a json, defined as a slice of byte
-func (c *Converter) Convert(swaggerSpec []byte) ([]byte, error) {
+//Convert expected a json input defined as a slice of byte, and returns a json, defined as a slice of byte.
+//The collection is encoded following the Encoding options of the config.
+//The returned report lists the parts of the spec which could not be fully converted.
+func (c *Converter) Convert(swaggerSpec []byte) ([]byte, *Report, error) {
+
+	pman, report, err := c.ConvertToCollection(context.Background(), swaggerSpec)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	specDoc, err := loads.Analyzed(swaggerSpec, "2.0")
+	out, err := EncodeCollection(pman, c.config.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return out, report, nil
+}
+
+//ConvertToCollection converts a swagger specification, defined as a slice of byte, to a postman collection.
+//The conversion stops as soon as the context is done.
+//The returned report lists the parts of the spec which could not be fully converted.
+func (c *Converter) ConvertToCollection(ctx context.Context, swaggerSpec []byte) (*postman2.Collection, *Report, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	swag, bodyDefs, err := expandSpec(ctx, swaggerSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conv := c.newConversion(swag, bodyDefs)
+
+	pman := postman2.NewCollection(strings.TrimSpace(swag.Info.Title), strings.TrimSpace(swag.Info.Description))
+
+	if err := conv.addUrls(ctx, swag.Paths.Paths, &pman); err != nil {
+		return nil, nil, err
+	}
+
+	if err := conv.transformCollection(&pman, swag); err != nil {
+		return nil, nil, err
+	}
+
+	return &pman, &conv.diagnostics, nil
+}
+
+//ConvertReader converts a swagger specification read from r to a postman collection, streamed to w.
+//The collection is encoded following the Encoding options of the config.
+//The conversion stops as soon as the context is done, including while the spec is expanded.
+//The returned report lists the parts of the spec which could not be fully converted.
+func (c *Converter) ConvertReader(ctx context.Context, r io.Reader, w io.Writer) (*Report, error) {
+
+	swaggerSpec, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
-	specDocExpand, err := specDoc.Expanded(&spec.ExpandOptions{
-		SkipSchemas:         false,
-		ContinueOnError:     true,
-		AbsoluteCircularRef: true,
-	})
+	pman, report, err := c.ConvertToCollection(ctx, swaggerSpec)
 	if err != nil {
 		return nil, err
 	}
 
-	swag := specDocExpand.Spec()
+	if err := WriteCollection(ctx, w, pman, c.config.Encoding); err != nil {
+		return nil, err
+	}
 
-	if c.config.Hostname == "" {
-		c.config.Hostname = strings.TrimSpace(swag.Host)
+	return report, nil
+}
+
+//expandSpec loads a swagger specification and expands its references. It also returns the definitions referenced
+//by body parameters, which are lost by the expansion.
+//Expansion can not be interrupted : when the context is done, expandSpec returns without waiting for it.
+func expandSpec(ctx context.Context, swaggerSpec []byte) (*spec.Swagger, map[string]string, error) {
+
+	type expansion struct {
+		swag     *spec.Swagger
+		bodyDefs map[string]string
+		err      error
 	}
 
-	if c.config.BasePath == "" {
-		c.config.BasePath = strings.TrimSpace(swag.BasePath)
+	done := make(chan expansion, 1)
+
+	if err := checkVersion(swaggerSpec); err != nil {
+		return nil, nil, err
 	}
 
-	//if schema is not defined in config, we take the first one declared on the swagger specs.
-	if c.config.Schema == "" && len(swag.Schemes) >= 1 {
-		c.config.Schema = strings.TrimSpace(swag.Schemes[0])
-	} else {
-		c.config.Schema = "http"
+	go func() {
+		specDoc, err := loads.Analyzed(swaggerSpec, "2.0")
+		if err != nil {
+			done <- expansion{err: &SchemaError{Location: "#", Err: err}}
+			return
+		}
+
+		bodyDefs := bodyDefinitions(specDoc.Spec())
+
+		specDocExpand, err := specDoc.Expanded(&spec.ExpandOptions{
+			SkipSchemas:         false,
+			ContinueOnError:     true,
+			AbsoluteCircularRef: true,
+		})
+		if err != nil {
+			done <- expansion{err: &SchemaError{Location: "#", Err: err}}
+			return
+		}
+
+		done <- expansion{swag: specDocExpand.Spec(), bodyDefs: bodyDefs}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case res := <-done:
+		return res.swag, res.bodyDefs, res.err
 	}
+}
 
-	pman := postman2.NewCollection(strings.TrimSpace(swag.Info.Title), strings.TrimSpace(swag.Info.Description))
+//checkVersion checks that a spec is a swagger 2.0 spec
+func checkVersion(swaggerSpec []byte) error {
 
-	if err := c.addUrls(swag.Paths.Paths, &pman); err != nil {
-		return nil, err
+	var header struct {
+		Swagger string `json:"swagger"`
+		OpenAPI string `json:"openapi"`
+	}
+
+	if err := json.Unmarshal(swaggerSpec, &header); err != nil {
+		return &SchemaError{Location: "#", Err: err}
+	}
+
+	switch {
+	case header.Swagger == "2.0":
+		return nil
+	case header.OpenAPI != "":
+		return fmt.Errorf("%w: openapi %s", ErrUnsupportedVersion, header.OpenAPI)
+	case header.Swagger != "":
+		return fmt.Errorf("%w: swagger %s", ErrUnsupportedVersion, header.Swagger)
+	}
+
+	return fmt.Errorf("%w: no swagger version defined", ErrUnsupportedVersion)
+}
+
+//newConversion creates the converter used for a single conversion of a swagger spec, whose body parameters
+//reference the given definitions.
+//Its config is completed with the hostname, base path and schema defined in the spec,
+//leaving the config of the original converter untouched.
+func (c *Converter) newConversion(swag *spec.Swagger, bodyDefinitions map[string]string) *Converter {
+
+	cfg := c.config
+
+	if cfg.Hostname == "" {
+		cfg.Hostname = strings.TrimSpace(swag.Host)
 	}
 
-	return json.MarshalIndent(pman, "", "  ")
+	if cfg.BasePath == "" {
+		cfg.BasePath = strings.TrimSpace(swag.BasePath)
+	}
+
+	//if schema is not defined in config, we take the first one declared on the swagger specs.
+	//A hostname made of a single postman variable, such as {{baseUrl}}, is expected to hold its own protocol.
+	if cfg.Schema == "" && !isPostmanVariable(cfg.Hostname) {
+		cfg.Schema = "http"
+		if len(swag.Schemes) >= 1 {
+			cfg.Schema = strings.TrimSpace(swag.Schemes[0])
+		}
+	}
 
+	return &Converter{
+		config:          cfg,
+		generator:       c.generator,
+		definitions:     swag.Definitions,
+		bodyDefinitions: bodyDefinitions,
+		consumes:        swag.Consumes,
+		produces:        swag.Produces,
+
+		rootExtensions: swag.Extensions,
+	}
 }
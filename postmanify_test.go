@@ -1,9 +1,19 @@
 package postmanify
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/go-openapi/spec"
 )
 
 func TestNewConverter(t *testing.T) {
@@ -11,3 +21,289 @@ func TestNewConverter(t *testing.T) {
 
 	assert.NotNil(t, conv)
 }
+
+func TestConvertRecursiveSchema(t *testing.T) {
+
+	swag := `{
+		"swagger": "2.0",
+		"info": {"title": "categories", "version": "1.0"},
+		"paths": {
+			"/categories": {
+				"post": {
+					"tags": ["category"],
+					"parameters": [
+						{"in": "body", "name": "body", "required": true, "schema": {"$ref": "#/definitions/Category"}}
+					]
+				}
+			}
+		},
+		"definitions": {
+			"Category": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"parent": {"$ref": "#/definitions/Category"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/Category"}}
+				}
+			}
+		}
+	}`
+
+	conv := NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}})
+
+	out, report, err := conv.Convert([]byte(swag))
+	assert.NoError(t, err)
+
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(out, &collection))
+	assert.Equal(t, indentJSON(`{"children":[],"name":"string","parent":"<recursive: Category>"}`), collection.Item[0].Item[0].Request.Body.Raw)
+	assert.Equal(t, []Diagnostic{
+		{Code: CodeRecursiveSchema, Severity: SeverityWarning, Location: "#/definitions/Category/properties/children", Message: "recursive schema Category is not expanded"},
+		{Code: CodeRecursiveSchema, Severity: SeverityWarning, Location: "#/definitions/Category/properties/parent", Message: "recursive schema Category is not expanded"},
+	}, report.Diagnostics)
+}
+
+func TestConvertMaxDepth(t *testing.T) {
+
+	swag := `{
+		"swagger": "2.0",
+		"info": {"title": "nested", "version": "1.0"},
+		"paths": {
+			"/nested": {
+				"post": {
+					"tags": ["nested"],
+					"parameters": [
+						{"in": "body", "name": "body", "required": true, "schema": {
+							"type": "object",
+							"properties": {
+								"a": {"type": "object", "properties": {
+									"b": {"type": "object", "properties": {
+										"c": {"type": "string"}
+									}}
+								}}
+							}
+						}}
+					]
+				}
+			}
+		}
+	}`
+
+	conv := NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}, MaxDepth: 2})
+
+	out, report, err := conv.Convert([]byte(swag))
+	assert.NoError(t, err)
+
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(out, &collection))
+	assert.Equal(t, indentJSON(`{"a":{"b":{}}}`), collection.Item[0].Item[0].Request.Body.Raw)
+	assert.Equal(t, []Diagnostic{
+		{Code: CodeMaxDepth, Severity: SeverityWarning, Location: "#/paths/~1nested/post/parameters/0/schema/properties/a/properties/b", Message: "schema nested deeper than 2 levels is not expanded"},
+	}, report.Diagnostics)
+}
+
+func TestConvertHeadersIsolation(t *testing.T) {
+
+	swag := `{
+		"swagger": "2.0",
+		"info": {"title": "headers", "version": "1.0"},
+		"paths": {
+			"/a": {
+				"post": {
+					"tags": ["test"],
+					"consumes": ["application/xml"],
+					"parameters": [{"in": "header", "name": "X-Only-A", "type": "string"}]
+				}
+			},
+			"/b": {
+				"get": {
+					"tags": ["test"]
+				}
+			}
+		}
+	}`
+
+	conv := NewConverter(Config{})
+
+	for i := 0; i < 5; i++ {
+		out, _, err := conv.Convert([]byte(swag))
+		assert.NoError(t, err)
+
+		var collection postman2.Collection
+		assert.NoError(t, json.Unmarshal(out, &collection))
+		assert.Equal(t, []postman2.Header{
+			{Key: "Content-Type", Value: "application/xml"},
+			{Key: "X-Only-A", Value: "string", Disabled: true},
+		}, collection.Item[0].Item[0].Request.Header)
+		assert.Empty(t, collection.Item[0].Item[1].Request.Header)
+	}
+}
+
+func TestConvertReuse(t *testing.T) {
+
+	swag := func(host, scheme string) []byte {
+		return []byte(`{
+			"swagger": "2.0",
+			"info": {"title": "` + host + `", "version": "1.0"},
+			"host": "` + host + `",
+			"basePath": "/v1",
+			"schemes": ["` + scheme + `"],
+			"paths": {"/users": {"get": {"tags": ["users"]}}}
+		}`)
+	}
+
+	conv := NewConverter(Config{})
+
+	dataset := []struct {
+		input    []byte
+		expected string
+	}{
+		{input: swag("first.example.com", "https"), expected: "https://first.example.com/v1/users"},
+		{input: swag("second.example.com", "http"), expected: "http://second.example.com/v1/users"},
+	}
+
+	for _, data := range dataset {
+		out, _, err := conv.Convert(data.input)
+		assert.NoError(t, err)
+
+		var collection postman2.Collection
+		assert.NoError(t, json.Unmarshal(out, &collection))
+		assert.Equal(t, data.expected, collection.Item[0].Item[0].Request.URL.Raw)
+	}
+
+	assert.Equal(t, Config{}, conv.config)
+
+	//a schema defined in the config is kept
+	out, _, err := NewConverter(Config{Schema: "https"}).Convert(swag("third.example.com", "http"))
+	assert.NoError(t, err)
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(out, &collection))
+	assert.Equal(t, "https://third.example.com/v1/users", collection.Item[0].Item[0].Request.URL.Raw)
+}
+
+func TestConvertConcurrent(t *testing.T) {
+
+	conv := NewConverter(Config{
+		PostmanHeaders: map[string]postman2.Header{
+			"Authorization": {Key: "Authorization", Value: "Bearer {{my_access_token}}"},
+		},
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			host := fmt.Sprintf("api%d.example.com", i)
+			out, report, err := conv.Convert([]byte(`{
+				"swagger": "2.0",
+				"info": {"title": "concurrent", "version": "1.0"},
+				"host": "` + host + `",
+				"paths": {"/users": {"post": {
+					"tags": ["users"],
+					"consumes": ["application/json"],
+					"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}]
+				}}}
+			}`))
+			assert.NoError(t, err)
+
+			var collection postman2.Collection
+			assert.NoError(t, json.Unmarshal(out, &collection))
+			assert.Equal(t, "http://"+host+"/users", collection.Item[0].Item[0].Request.URL.Raw)
+			assert.Len(t, collection.Item[0].Item[0].Request.Header, 2)
+			assert.Empty(t, report.Diagnostics)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestConvertToCollection(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "users", "description": "users api", "version": "1.0"},
+		"host": "api.example.com",
+		"paths": {"/users": {"get": {"tags": ["users"]}}}
+	}`)
+
+	conv := NewConverter(Config{})
+
+	collection, report, err := conv.ConvertToCollection(context.Background(), swag)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Diagnostics)
+	assert.Equal(t, "users", collection.Info.Name)
+	assert.Equal(t, "users api", collection.Info.Description)
+	assert.Equal(t, "users", collection.Item[0].Name)
+	assert.Equal(t, "http://api.example.com/users", collection.Item[0].Item[0].Request.URL.Raw)
+
+	//Convert is a thin wrapper around ConvertToCollection
+	out, _, err := conv.Convert(swag)
+	assert.NoError(t, err)
+	expected, err := EncodeCollection(collection, EncodeOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(out))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	collection, report, err = conv.ConvertToCollection(ctx, swag)
+	assert.Nil(t, collection)
+	assert.Nil(t, report)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestConvertReader(t *testing.T) {
+
+	swag := `{
+		"swagger": "2.0",
+		"info": {"title": "users", "version": "1.0"},
+		"host": "api.example.com",
+		"paths": {
+			"/users": {"get": {"tags": ["users"]}},
+			"/groups": {"get": {"tags": ["groups"]}}
+		}
+	}`
+
+	conv := NewConverter(Config{})
+
+	var out bytes.Buffer
+	_, err := conv.ConvertReader(context.Background(), strings.NewReader(swag), &out)
+	assert.NoError(t, err)
+
+	expected, _, err := conv.Convert([]byte(swag))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), out.String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	out.Reset()
+	_, err = conv.ConvertReader(ctx, strings.NewReader(swag), &out)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Empty(t, out.String())
+}
+
+func TestNewConversionSchema(t *testing.T) {
+
+	dataset := []struct {
+		cfg      Config
+		schemes  []string
+		expected string
+	}{
+		{cfg: Config{}, expected: "http"},
+		{cfg: Config{}, schemes: []string{"https", "http"}, expected: "https"},
+		{cfg: Config{Schema: "ws"}, schemes: []string{"https"}, expected: "ws"},
+		{cfg: Config{Hostname: "{{baseUrl}}"}, schemes: []string{"https"}, expected: ""},
+		{cfg: Config{Hostname: "{{baseUrl}}", Schema: "https"}, expected: "https"},
+		{cfg: Config{Hostname: "{{region}}.{{domain}}"}, expected: "http"},
+	}
+
+	for _, data := range dataset {
+		swag := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Schemes: data.schemes}}
+		assert.Equal(t, data.expected, NewConverter(data.cfg).newConversion(swag, nil).config.Schema)
+	}
+}
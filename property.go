@@ -1,93 +1,318 @@
 package postmanify
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"sort"
-	"time"
+	"strings"
 
 	"github.com/go-openapi/spec"
 )
 
+const (
+	//additionalPropertyKey is the placeholder key used to render map-style objects (additionalProperties)
+	additionalPropertyKey = "key1"
+	//freeFormPropertyValue is the placeholder value used to render free-form objects
+	freeFormPropertyValue = "value1"
+	//defaultMaxDepth is the maximum schema nesting depth rendered when none is defined in the config
+	defaultMaxDepth = 10
+)
+
+//schemaTrail keeps track of the schema being rendered : its nesting depth and the definitions
+//currently being expanded. It is used to detect cycles in recursive schemas.
+//The trail also holds the name of the property being rendered, used to generate sample values,
+//and its location in the swagger spec, used to report diagnostics.
+type schemaTrail struct {
+	depth    int
+	refs     []string
+	name     string
+	location string
+}
+
+//newSchemaTrail creates the trail of a root schema, located at the given JSON pointer
+func newSchemaTrail(location string) schemaTrail {
+	return schemaTrail{location: location}
+}
+
+//enter returns the trail of a nested object
+func (t schemaTrail) enter() schemaTrail {
+	t.depth++
+	return t
+}
+
+//items returns the trail of array items
+func (t schemaTrail) items() schemaTrail {
+	t.depth++
+	t.location = appendPointer(t.location, "items")
+	return t
+}
+
+//follow returns the trail of a referenced definition
+func (t schemaTrail) follow(name string) schemaTrail {
+	refs := make([]string, len(t.refs), len(t.refs)+1)
+	copy(refs, t.refs)
+	t.refs = append(refs, name)
+	t.location = jsonPointer("definitions", name)
+	return t
+}
+
+//property returns the trail of an object property, located under the given tokens
+func (t schemaTrail) property(name string, tokens ...string) schemaTrail {
+	t.name = name
+	t.location = appendPointer(t.location, tokens...)
+	return t
+}
+
+//visiting checks if a definition is already being expanded
+func (t schemaTrail) visiting(name string) bool {
+	for _, ref := range t.refs {
+		if ref == name {
+			return true
+		}
+	}
+	return false
+}
+
 //buildProperties creates a json request body from a map of swagger Schema
 func (c *Converter) buildProperties(properties map[string]spec.Schema) string {
 
-	body := make(map[string]interface{})
+	b, err := marshalIndent(c.buildObjectValue(spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties:           properties,
+			AdditionalProperties: &spec.SchemaOrBool{Allows: false},
+		},
+	}, schemaTrail{}))
+	if err != nil {
+		c.reportError(SeverityError, CodeEncodingFailed, "", &SchemaError{Location: "#", Err: err}, "unable to encode properties: %s", err)
+		return ""
+	}
 
-	keys := []string{}
-	for key := range properties {
-		keys = append(keys, key)
+	return string(b)
+}
+
+//marshalIndent encodes a generated value as indented json, without escaping html characters.
+//Dynamic variables are rendered unquoted.
+func marshalIndent(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(markDynamicVariables(v)); err != nil {
+		return nil, err
 	}
+	b := dynamicVariableMarker.ReplaceAll(buf.Bytes(), []byte("$1"))
+	return bytes.TrimSuffix(b, []byte("\n")), nil
+}
 
-	sort.Strings(keys)
+//dynamicVariableMarker matches the json strings produced by markDynamicVariables
+var dynamicVariableMarker = regexp.MustCompile(`"\\u0000(.*?)\\u0000"`)
 
-	for _, key := range keys {
-		prop := properties[key]
+//markDynamicVariables replaces dynamic variables of a generated value by marked strings, to be unquoted once encoded
+func markDynamicVariables(v interface{}) interface{} {
+	switch value := v.(type) {
+	case dynamicVariable:
+		return "\x00" + string(value) + "\x00"
+	case map[string]interface{}:
+		marked := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			marked[k] = markDynamicVariables(e)
+		}
+		return marked
+	case []interface{}:
+		marked := make([]interface{}, len(value))
+		for i, e := range value {
+			marked[i] = markDynamicVariables(e)
+		}
+		return marked
+	}
+	return v
+}
+
+//buildSchemaValue generates a sample value from a swagger Schema.
+//Circular references, left unresolved by the spec expansion, are resolved against the spec definitions.
+//A definition referencing itself is rendered as a "<recursive: Name>" marker (or an empty array when
+//used as array items), and schemas nested deeper than the configured MaxDepth are rendered empty.
+func (c *Converter) buildSchemaValue(prop spec.Schema, trail schemaTrail) interface{} {
 
-		//Property as an example value : we take it as value
-		if prop.Example != nil {
-			body[key] = prop.Example
-			continue
+	if ref := prop.Ref.String(); ref != "" {
+		name := definitionName(prop.Ref)
+		definition, ok := c.definitions[name]
+		if !ok {
+			c.reportError(SeverityWarning, CodeUnresolvedRef, trail.location, &RefResolutionError{Ref: ref, Location: trail.location}, "unable to resolve schema reference %s", ref)
+			return ""
+		}
+		if trail.visiting(name) {
+			c.report(SeverityWarning, CodeRecursiveSchema, trail.location, "recursive schema %s is not expanded", name)
+			return fmt.Sprintf("<recursive: %s>", name)
 		}
+		return c.buildSchemaValue(definition, trail.follow(name))
+	}
+
+	unsupported := []struct {
+		keyword string
+		used    bool
+	}{
+		{"allOf", len(prop.AllOf) > 0},
+		{"anyOf", len(prop.AnyOf) > 0},
+		{"oneOf", len(prop.OneOf) > 0},
+		{"not", prop.Not != nil},
+	}
 
-		//Property as a Enum : we take the first possible value
-		//Note: we only support string enum for now;
-		//TODO : add support for other type enum.
-		if prop.Type.Contains("string") && len(prop.Enum) > 0 {
-			body[key] = prop.Enum[0]
-			continue
+	for _, construct := range unsupported {
+		if construct.used {
+			c.report(SeverityWarning, CodeUnsupportedSchema, trail.location, "%s is not supported and is ignored", construct.keyword)
 		}
+	}
+
+	//Property as a x-postman-value extension : it overrides any other value
+	if value, ok := postmanValue(prop.Extensions, schemaType(prop.Type)); ok {
+		return value
+	}
+
+	//Property as an example value : we take it as value
+	if prop.Example != nil {
+		return prop.Example
+	}
 
-		if prop.Type.Contains("object") {
-			body[key] = json.RawMessage(c.buildProperties(prop.Properties))
-			continue
+	//Property as a Enum : we take the first possible value
+	//Note: we only support string enum for now;
+	//TODO : add support for other type enum.
+	if prop.Type.Contains("string") && len(prop.Enum) > 0 {
+		return prop.Enum[0]
+	}
+
+	if prop.Type.Contains("object") {
+		if trail.depth >= c.maxDepth() {
+			c.report(SeverityWarning, CodeMaxDepth, trail.location, "schema nested deeper than %d levels is not expanded", c.maxDepth())
+			return map[string]interface{}{}
 		}
+		return c.buildObjectValue(prop, trail.enter())
+	}
 
-		if prop.Type.Contains("array") {
-			if prop.Items.ContainsType("object") {
-				array := []json.RawMessage{json.RawMessage(c.buildProperties(prop.Items.Schema.Properties))}
-				rawArray, _ := json.MarshalIndent(array, "", "\t")
-				body[key] = json.RawMessage(rawArray)
-				continue
+	if prop.Type.Contains("array") {
+		array := []interface{}{}
+		if trail.depth >= c.maxDepth() {
+			c.report(SeverityWarning, CodeMaxDepth, trail.location, "schema nested deeper than %d levels is not expanded", c.maxDepth())
+			return array
+		}
+		if prop.Items != nil && prop.Items.Schema != nil {
+			//a recursive array renders as an empty array
+			if name := definitionName(prop.Items.Schema.Ref); name != "" && trail.visiting(name) {
+				c.report(SeverityWarning, CodeRecursiveSchema, trail.location, "recursive schema %s is not expanded", name)
+				return array
 			}
-			var array []interface{}
-			array = append(array, buildPropertyDefaultValue(prop.Items.Schema.Type, prop.Items.Schema.Format))
-			rawArray, _ := json.MarshalIndent(array, "", "\t")
-			body[key] = json.RawMessage(rawArray)
-			continue
+			array = append(array, c.buildSchemaValue(*prop.Items.Schema, trail.items()))
 		}
+		return array
+	}
+
+	return c.generateValue(trail.name, schemaType(prop.Type), prop.Format)
+}
+
+//buildObjectValue generates a sample object from a swagger object Schema.
+//Named properties are rendered first, then patternProperties and additionalProperties
+//are rendered using a placeholder key.
+//A free-form object (no properties at all) is rendered with a placeholder key and value.
+func (c *Converter) buildObjectValue(prop spec.Schema, trail schemaTrail) map[string]interface{} {
+
+	body := make(map[string]interface{})
+
+	keys := []string{}
+	for key := range prop.Properties {
+		keys = append(keys, key)
+	}
 
-		body[key] = buildPropertyDefaultValue(prop.Type, prop.Format)
+	sort.Strings(keys)
 
+	for _, key := range keys {
+		body[key] = c.buildSchemaValue(prop.Properties[key], trail.property(key, "properties", key))
 	}
 
-	b, err := json.MarshalIndent(body, "", "\t")
-	if err != nil {
-		panic(err)
+	patterns := []string{}
+	for pattern := range prop.PatternProperties {
+		patterns = append(patterns, pattern)
 	}
 
-	return string(b)
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		key := patternPropertyKey(pattern)
+		body[key] = c.buildSchemaValue(prop.PatternProperties[pattern], trail.property(key, "patternProperties", pattern))
+	}
+
+	additional := prop.AdditionalProperties
+	if additional != nil && additional.Schema != nil {
+		body[additionalPropertyKey] = c.buildSchemaValue(*additional.Schema, trail.property(additionalPropertyKey, "additionalProperties"))
+		return body
+	}
+
+	//free-form object : additionalProperties is either absent or true
+	if len(body) == 0 && (additional == nil || additional.Allows) {
+		body[additionalPropertyKey] = freeFormPropertyValue
+	}
+
+	return body
 }
 
-//buildPropertyDefaultValue generate default values for Swagger schema where no example or default are defined.
-func buildPropertyDefaultValue(propType spec.StringOrArray, propFormat string) interface{} {
+//resolveSchema returns the definition targeted by a schema reference, or the schema itself
+func (c *Converter) resolveSchema(schema spec.Schema) spec.Schema {
+	if definition, ok := c.definitions[definitionName(schema.Ref)]; ok {
+		return definition
+	}
+	return schema
+}
 
-	if propType == nil {
+//maxDepth returns the maximum schema nesting depth to render
+func (c *Converter) maxDepth() int {
+	if c.config.MaxDepth > 0 {
+		return c.config.MaxDepth
+	}
+	return defaultMaxDepth
+}
+
+//definitionName returns the name of the definition targeted by a "#/definitions/Name" reference
+func definitionName(ref spec.Ref) string {
+	if ref.String() == "" || ref.GetPointer() == nil {
 		return ""
 	}
 
-	//Property has no example value : we set one by default
-	if propType.Contains("integer") {
-		return 0
+	tokens := ref.GetPointer().DecodedTokens()
+	if len(tokens) != 2 || tokens[0] != "definitions" {
+		return ""
 	}
 
-	if propType.Contains("string") {
-		switch propFormat {
-		case "date-time":
-			return time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC).Format(time.RFC3339)
-		default:
-			return "string"
-		}
+	return tokens[1]
+}
+
+//patternPropertyKey builds a sample key matching a patternProperties regular expression.
+//It keeps the literal prefix of the pattern and completes it with a placeholder key when needed.
+func patternPropertyKey(pattern string) string {
+
+	literal := strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")
+
+	if i := strings.IndexAny(literal, `.[]()*+?{}|\^$`); i >= 0 {
+		return literal[:i] + additionalPropertyKey
+	}
+
+	//pattern is not anchored at the end : any suffix matches
+	if !strings.HasSuffix(pattern, "$") {
+		return literal + additionalPropertyKey
 	}
 
+	return literal
+}
+
+//schemaType returns the main type of a swagger Schema. Integer types take precedence over strings.
+func schemaType(propType spec.StringOrArray) string {
+	for _, t := range []string{"integer", "string"} {
+		if propType.Contains(t) {
+			return t
+		}
+	}
+	if len(propType) > 0 {
+		return propType[0]
+	}
 	return ""
 }
@@ -180,3 +180,96 @@ func indentJSON(s string) string {
 	}
 	return string(out.Bytes())
 }
+
+func TestBuildPropertiesAdditionalProperties(t *testing.T) {
+
+	tag := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"name": spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"string"},
+					},
+				},
+			},
+		},
+	}
+
+	dataset := []struct {
+		input    map[string]spec.Schema
+		expected string
+	}{
+		{
+			input: map[string]spec.Schema{
+				"tags": spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type:                 spec.StringOrArray{"object"},
+						AdditionalProperties: &spec.SchemaOrBool{Allows: true, Schema: &tag},
+					},
+				},
+			},
+			expected: indentJSON(`{"tags":{"key1":{"name":"string"}}}`),
+		},
+		{
+			input: map[string]spec.Schema{
+				"labels": spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						PatternProperties: map[string]spec.Schema{
+							"^x-": spec.Schema{
+								SchemaProps: spec.SchemaProps{
+									Type: spec.StringOrArray{"integer"},
+								},
+							},
+							"^[a-z]+$": spec.Schema{
+								SchemaProps: spec.SchemaProps{
+									Type: spec.StringOrArray{"string"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: indentJSON(`{"labels":{"key1":"string","x-key1":0}}`),
+		},
+		{
+			input: map[string]spec.Schema{
+				"metadata": spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+					},
+				},
+				"empty": spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type:                 spec.StringOrArray{"object"},
+						AdditionalProperties: &spec.SchemaOrBool{Allows: false},
+					},
+				},
+			},
+			expected: indentJSON(`{"empty":{},"metadata":{"key1":"value1"}}`),
+		},
+	}
+
+	for _, data := range dataset {
+		conv := NewConverter(Config{})
+		assert.Equal(t, data.expected, conv.buildProperties(data.input))
+	}
+}
+
+func TestPatternPropertyKey(t *testing.T) {
+
+	dataset := []struct {
+		input    string
+		expected string
+	}{
+		{input: "^x-", expected: "x-key1"},
+		{input: "^[a-z]+$", expected: "key1"},
+		{input: "^S_.*$", expected: "S_key1"},
+		{input: "^literal$", expected: "literal"},
+	}
+
+	for _, data := range dataset {
+		assert.Equal(t, data.expected, patternPropertyKey(data.input))
+	}
+}
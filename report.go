@@ -0,0 +1,121 @@
+package postmanify
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Severity represents the severity of a conversion diagnostic
+type Severity string
+
+const (
+	//SeverityInfo is used for spec constructs converted with a fallback, without any loss
+	SeverityInfo Severity = "info"
+	//SeverityWarning is used for spec constructs partially converted, or ignored
+	SeverityWarning Severity = "warning"
+	//SeverityError is used for spec constructs which could not be converted at all
+	SeverityError Severity = "error"
+)
+
+//Diagnostic codes, identifying the kind of lossy conversion
+const (
+	//CodeUntaggedOperation is used for operations without any tag, which are not converted
+	CodeUntaggedOperation = "untagged-operation"
+	//CodeUnsupportedOperation is used for operations using an unsupported method, such as HEAD or OPTIONS
+	CodeUnsupportedOperation = "unsupported-operation"
+	//CodeUnresolvedRef is used for schema references which could not be resolved
+	CodeUnresolvedRef = "unresolved-ref"
+	//CodeRecursiveSchema is used for recursive schemas, rendered as a marker
+	CodeRecursiveSchema = "recursive-schema"
+	//CodeMaxDepth is used for schemas nested deeper than the configured max depth, rendered empty
+	CodeMaxDepth = "max-depth"
+	//CodeUnsupportedSchema is used for schema constructs which are ignored, such as allOf or oneOf
+	CodeUnsupportedSchema = "unsupported-schema"
+	//CodeUnsupportedParameter is used for parameters which can not be sent, such as a file in a urlencoded body
+	CodeUnsupportedParameter = "unsupported-parameter"
+	//CodeInvalidValue is used for default or example values which do not match their parameter type
+	CodeInvalidValue = "invalid-value"
+	//CodeEncodingFailed is used for values which could not be encoded, such as a body or an example
+	CodeEncodingFailed = "encoding-failed"
+)
+
+//Diagnostic represents a lossy conversion of a part of a swagger spec
+type Diagnostic struct {
+	//Code identifies the kind of diagnostic
+	Code string `json:"code"`
+	//Severity is the diagnostic severity
+	Severity Severity `json:"severity"`
+	//Location is the JSON pointer to the part of the swagger spec, such as #/paths/~1users/post/parameters/0
+	Location string `json:"location,omitempty"`
+	//Message describes the diagnostic
+	Message string `json:"message"`
+	//Err is the typed error causing the diagnostic, such as a *RefResolutionError or a *SchemaError, if any
+	Err error `json:"-"`
+}
+
+//String formats a diagnostic as "severity [code] location: message"
+func (d Diagnostic) String() string {
+	if d.Location == "" {
+		return fmt.Sprintf("%s [%s] %s", d.Severity, d.Code, d.Message)
+	}
+	return fmt.Sprintf("%s [%s] %s: %s", d.Severity, d.Code, d.Location, d.Message)
+}
+
+//Report lists the diagnostics produced by a conversion
+type Report struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+//HasWarnings checks if the report holds diagnostics with a warning or error severity
+func (r *Report) HasWarnings() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityWarning || d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+//add adds a diagnostic to the report, once
+func (r *Report) add(d Diagnostic) {
+	for _, existing := range r.Diagnostics {
+		if existing.Code == d.Code && existing.Severity == d.Severity && existing.Location == d.Location && existing.Message == d.Message {
+			return
+		}
+	}
+	r.Diagnostics = append(r.Diagnostics, d)
+}
+
+//report records a conversion diagnostic
+func (c *Converter) report(severity Severity, code, location, format string, args ...interface{}) {
+	c.reportError(severity, code, location, nil, format, args...)
+}
+
+//reportError records a conversion diagnostic caused by a typed error
+func (c *Converter) reportError(severity Severity, code, location string, err error, format string, args ...interface{}) {
+	c.diagnostics.add(Diagnostic{
+		Code:     code,
+		Severity: severity,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+		Err:      err,
+	})
+}
+
+//jsonPointer builds a JSON pointer to a part of the swagger spec, such as #/paths/~1users/post
+func jsonPointer(tokens ...string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	escaped := make([]string, len(tokens))
+	for i, token := range tokens {
+		escaped[i] = escaper.Replace(token)
+	}
+	return "#/" + strings.Join(escaped, "/")
+}
+
+//appendPointer appends tokens to a JSON pointer
+func appendPointer(pointer string, tokens ...string) string {
+	if pointer == "" {
+		return ""
+	}
+	return pointer + strings.TrimPrefix(jsonPointer(tokens...), "#")
+}
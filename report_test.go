@@ -0,0 +1,95 @@
+package postmanify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPointer(t *testing.T) {
+
+	dataset := []struct {
+		tokens   []string
+		expected string
+	}{
+		{tokens: []string{"paths", "/users/{id}", "get"}, expected: "#/paths/~1users~1{id}/get"},
+		{tokens: []string{"definitions", "a~b"}, expected: "#/definitions/a~0b"},
+	}
+
+	for _, data := range dataset {
+		assert.Equal(t, data.expected, jsonPointer(data.tokens...))
+	}
+
+	assert.Equal(t, "#/paths/~1users/get/parameters/0", appendPointer("#/paths/~1users/get", "parameters", "0"))
+	assert.Equal(t, "", appendPointer("", "parameters", "0"))
+}
+
+func TestDiagnosticString(t *testing.T) {
+
+	dataset := []struct {
+		input    Diagnostic
+		expected string
+	}{
+		{
+			input:    Diagnostic{Code: CodeUnresolvedRef, Severity: SeverityWarning, Location: "#/definitions/User", Message: "unable to resolve schema reference"},
+			expected: "warning [unresolved-ref] #/definitions/User: unable to resolve schema reference",
+		},
+		{
+			input:    Diagnostic{Code: CodeEncodingFailed, Severity: SeverityError, Message: "unable to encode properties"},
+			expected: "error [encoding-failed] unable to encode properties",
+		},
+	}
+
+	for _, data := range dataset {
+		assert.Equal(t, data.expected, data.input.String())
+	}
+}
+
+func TestReportHasWarnings(t *testing.T) {
+
+	report := Report{}
+	assert.False(t, report.HasWarnings())
+
+	report.add(Diagnostic{Code: CodeInvalidValue, Severity: SeverityInfo})
+	report.add(Diagnostic{Code: CodeInvalidValue, Severity: SeverityInfo})
+	assert.Len(t, report.Diagnostics, 1)
+	assert.False(t, report.HasWarnings())
+
+	report.add(Diagnostic{Code: CodeMaxDepth, Severity: SeverityWarning})
+	assert.True(t, report.HasWarnings())
+}
+
+func TestConvertReport(t *testing.T) {
+
+	swag := `{
+		"swagger": "2.0",
+		"info": {"title": "report", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"get": {},
+				"head": {"tags": ["users"]},
+				"post": {
+					"tags": ["users"],
+					"parameters": [
+						{"in": "header", "name": "X-Page", "type": "integer", "default": 1},
+						{"in": "body", "name": "body", "schema": {
+							"type": "object",
+							"properties": {
+								"pet": {"allOf": [{"type": "object"}]}
+							}
+						}}
+					]
+				}
+			}
+		}
+	}`
+
+	_, report, err := NewConverter(Config{}).Convert([]byte(swag))
+	assert.NoError(t, err)
+	assert.Equal(t, []Diagnostic{
+		{Code: CodeUntaggedOperation, Severity: SeverityWarning, Location: "#/paths/~1users/get", Message: "operation has no tag and is not converted"},
+		{Code: CodeInvalidValue, Severity: SeverityInfo, Location: "#/paths/~1users/post/parameters/0/default", Message: "value 1 is not a string and is formatted as is"},
+		{Code: CodeUnsupportedSchema, Severity: SeverityWarning, Location: "#/paths/~1users/post/parameters/1/schema/properties/pet", Message: "allOf is not supported and is ignored"},
+		{Code: CodeUnsupportedOperation, Severity: SeverityWarning, Location: "#/paths/~1users/head", Message: "HEAD operations are not converted"},
+	}, report.Diagnostics)
+}
@@ -2,33 +2,293 @@ package postmanify
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/seblegall/postmanify/postman2"
 	"github.com/go-openapi/spec"
 )
 
-//buildPostmanItem builds an item of a postman collection from a given path, method and a swagger Operation
+//buildPostmanItems builds the items of a postman collection from a given path, method and a swagger Operation.
+//When content negotiation expansion is enabled, a request is built for each consumed and produced media type.
+//When the operation documents named examples, each example becomes its own request, named after the example,
+//unless its body is overridden by a "x-postman-body" extension.
+//Otherwise, a single request is built.
+func (c *Converter) buildPostmanItems(url, method string, operation *spec.Operation) []postman2.APIItem {
+
+	operation = c.withDefaultMediaTypes(operation)
+
+	var items []postman2.APIItem
+
+	for _, variant := range c.buildContentVariants(operation) {
+		items = append(items, c.buildExampleItems(url, method, variant.operation, variant.labels)...)
+	}
+
+	return items
+}
+
+//buildExampleItems builds an item for each named example of a swagger Operation, or a single item
+//when the operation documents none. Items are named after the request name and the given labels.
+func (c *Converter) buildExampleItems(url, method string, operation *spec.Operation, labels []string) []postman2.APIItem {
+
+	item := c.buildPostmanItem(url, method, operation)
+	name := item.Name
+	item.Name = itemName(name, labels...)
+
+	//a literal "x-postman-body" overrides the examples too
+	if _, ok := c.postmanBody(operation); ok {
+		return []postman2.APIItem{item}
+	}
+
+	examples := buildNamedExamples(operation)
+	if len(examples) == 0 || item.Request.Body.Mode != "raw" {
+		return []postman2.APIItem{item}
+	}
+
+	var items []postman2.APIItem
+
+	for _, example := range examples {
+		raw, err := c.serializeBody(consumedRawMediaType(operation.Consumes), c.bodyDefinition(operation), bodySchema(operation), example.Value)
+		if err != nil {
+			c.reportError(SeverityError, CodeEncodingFailed, c.location, &SchemaError{Location: c.location, Err: err}, "unable to encode example %s: %s", example.Name, err)
+			continue
+		}
+
+		exampleItem := copyItem(item)
+		exampleItem.Name = itemName(name, append([]string{example.Name}, labels...)...)
+		if example.Summary != "" {
+			exampleItem.Request.Description = example.Summary
+		}
+		exampleItem.Request.Body.Raw = raw
+
+		items = append(items, exampleItem)
+	}
+
+	return items
+}
+
+//copyItem copies an item, including its slices, maps and pointers, so that each copy may be transformed on its own
+func copyItem(item postman2.APIItem) postman2.APIItem {
+
+	item.Event = append([]postman2.Event(nil), item.Event...)
+	for i := range item.Event {
+		item.Event[i].Script.Exec = append([]string(nil), item.Event[i].Script.Exec...)
+	}
+
+	request := &item.Request
+	request.Header = append([]postman2.Header(nil), request.Header...)
+
+	if request.Auth != nil {
+		auth := *request.Auth
+		auth.Attributes = append([]postman2.AuthAttribute(nil), auth.Attributes...)
+		request.Auth = &auth
+	}
+
+	url := &request.URL
+	url.Host = append([]string(nil), url.Host...)
+	url.Path = append([]string(nil), url.Path...)
+	url.Query = append([]postman2.URLQueryParam(nil), url.Query...)
+	if url.Variable != nil {
+		url.Variable = append([]postman2.URLVariable{}, url.Variable...)
+	}
+	if url.Auth != nil {
+		auth := make(map[string]string, len(url.Auth))
+		for key, value := range url.Auth {
+			auth[key] = value
+		}
+		url.Auth = auth
+	}
+
+	body := &request.Body
+	body.URLEncoded = append([]postman2.URLEncodedParam(nil), body.URLEncoded...)
+	body.FormData = append([]postman2.FormData(nil), body.FormData...)
+	if body.File != nil {
+		file := *body.File
+		body.File = &file
+	}
+	if body.Options != nil {
+		options := *body.Options
+		body.Options = &options
+	}
+
+	return item
+}
+
+//contentVariant represents a swagger Operation restricted to a single consumed and produced media type
+type contentVariant struct {
+	operation *spec.Operation
+	labels    []string
+}
+
+//buildContentVariants expands a swagger Operation into a variant per consumed and produced media type.
+//Consumed media types are only expanded for requests with a body.
+//When expansion is disabled, or when the operation has a single media type of each kind, the operation is kept as is.
+func (c *Converter) buildContentVariants(operation *spec.Operation) []contentVariant {
+
+	consumes, produces := operation.Consumes, operation.Produces
+	if !c.requestHasBody(operation) {
+		consumes = nil
+	}
+
+	if !c.config.ExpandContentTypes || (len(consumes) <= 1 && len(produces) <= 1) {
+		return []contentVariant{{operation: operation}}
+	}
+
+	if len(consumes) == 0 {
+		consumes = []string{""}
+	}
+	if len(produces) == 0 {
+		produces = []string{""}
+	}
+
+	var variants []contentVariant
+
+	for _, contentType := range consumes {
+		for _, accept := range produces {
+			variant := *operation
+			var labels []string
+
+			if contentType != "" {
+				variant.Consumes = []string{contentType}
+			}
+			if len(consumes) > 1 {
+				labels = append(labels, "Content-Type: "+strings.TrimSpace(contentType))
+			}
+
+			if accept != "" {
+				variant.Produces = []string{accept}
+			}
+			if len(produces) > 1 {
+				labels = append(labels, "Accept: "+strings.TrimSpace(accept))
+			}
+
+			variants = append(variants, contentVariant{operation: &variant, labels: labels})
+		}
+	}
+
+	return variants
+}
+
+//withDefaultMediaTypes returns a swagger Operation using the root-level consumed and produced media types
+//when it defines none. Consumed media types are only inherited by requests with a body.
+func (c *Converter) withDefaultMediaTypes(operation *spec.Operation) *spec.Operation {
+
+	hasBody := c.requestHasBody(operation)
+	if (len(operation.Consumes) > 0 || len(c.consumes) == 0 || !hasBody) && (len(operation.Produces) > 0 || len(c.produces) == 0) {
+		return operation
+	}
+
+	op := *operation
+	if len(op.Consumes) == 0 && hasBody {
+		op.Consumes = c.consumes
+	}
+	if len(op.Produces) == 0 {
+		op.Produces = c.produces
+	}
+
+	return &op
+}
+
+//withPathParameters returns a swagger Operation holding the parameters of its path item, unless the operation
+//overrides them with a parameter of the same name and location. Operation parameters come first.
+//The location of each parameter in the spec is kept, to report diagnostics.
+func (c *Converter) withPathParameters(url string, pathItem spec.PathItem, operation *spec.Operation) *spec.Operation {
+
+	c.parameterLocations = nil
+	for i := range operation.Parameters {
+		c.parameterLocations = append(c.parameterLocations, appendPointer(c.location, "parameters", strconv.Itoa(i)))
+	}
+
+	if len(pathItem.Parameters) == 0 {
+		return operation
+	}
+
+	overridden := make(map[string]bool)
+	for _, param := range operation.Parameters {
+		overridden[param.In+":"+param.Name] = true
+	}
+
+	op := *operation
+	op.Parameters = append([]spec.Parameter{}, operation.Parameters...)
+
+	for i, param := range pathItem.Parameters {
+		if overridden[param.In+":"+param.Name] {
+			continue
+		}
+		op.Parameters = append(op.Parameters, param)
+		c.parameterLocations = append(c.parameterLocations, jsonPointer("paths", url, "parameters", strconv.Itoa(i)))
+	}
+
+	return &op
+}
+
+//parameterLocation returns the JSON pointer to a parameter of the operation being converted
+func (c *Converter) parameterLocation(i int) string {
+	if i < len(c.parameterLocations) {
+		return c.parameterLocations[i]
+	}
+	return appendPointer(c.location, "parameters", strconv.Itoa(i))
+}
+
+//itemName names an item after its name, followed by labels between parenthesis
+func itemName(name string, labels ...string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(labels, ", "))
+}
+
+//bodySchema returns the schema of the body parameter of a swagger Operation
+func bodySchema(operation *spec.Operation) spec.Schema {
+	for _, param := range operation.Parameters {
+		if param.In == "body" && param.Schema != nil {
+			return *param.Schema
+		}
+	}
+	return spec.Schema{}
+}
+
+//buildPostmanItem builds an item of a postman collection from a given path, method and a swagger Operation.
+//The item may be customized with "x-postman-*" extensions, inherited from the path and the root of the spec.
 func (c *Converter) buildPostmanItem(url, method string, operation *spec.Operation) postman2.APIItem {
 
 	//build request
 	request := postman2.Request{
-		Method: strings.ToUpper(method),
-		URL:    c.buildPostmanURL(url, operation),
-		Header: c.buildPostmanHeaders(operation),
+		Method:      strings.ToUpper(method),
+		URL:         c.buildPostmanURL(url, operation),
+		Header:      c.buildPostmanHeaders(operation),
+		Auth:        c.postmanAuth(operation),
+		Description: c.postmanString(operation, postmanDescriptionExtension),
 	}
 
-	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+	//a body is built for methods expecting one, and for any other operation documenting one (such as a DELETE)
+	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || operationHasBody(operation) {
 		request.Body = c.buildPostmanBody(operation)
 	}
 
+	//a literal body overrides the generated one
+	if raw, ok := c.postmanBody(operation); ok {
+		request.Body = postman2.RequestBody{
+			Mode:    "raw",
+			Raw:     raw,
+			Options: &postman2.BodyOptions{Raw: postman2.RawOptions{Language: rawLanguage(consumedRawMediaType(operation.Consumes))}},
+		}
+	}
+
 	//build item
 	item := postman2.APIItem{
-		Name:    url,
+		Name:    c.requestName(url, method, operation),
 		Request: request,
 	}
 
+	if name := c.postmanString(operation, postmanNameExtension); name != "" {
+		item.Name = name
+	}
+
 	if script := buildPostmanScript(operation.Extensions); len(script.Exec) > 0 {
 		item.Event = []postman2.Event{
 			{
@@ -42,102 +302,208 @@ func (c *Converter) buildPostmanItem(url, method string, operation *spec.Operati
 
 }
 
-//buildPostmanHeaders builds headers from a swagger operation
+//operationHasBody checks if a swagger operation documents a request body, as a body or a formData parameter
+func operationHasBody(operation *spec.Operation) bool {
+	for _, param := range operation.Parameters {
+		if param.In == "body" || param.In == "formData" {
+			return true
+		}
+	}
+	return false
+}
+
+//requestHasBody checks if the request of a swagger operation has a body, documented by the operation or set by a
+//"x-postman-body" extension
+func (c *Converter) requestHasBody(operation *spec.Operation) bool {
+	if operationHasBody(operation) {
+		return true
+	}
+	_, ok := c.postmanExtension(operation, postmanBodyExtension)
+	return ok
+}
+
+//buildPostmanHeaders builds headers from a swagger operation.
+//Headers are computed for each operation, without altering the global headers defined in the config.
+//They are ordered as follows : global headers sorted by key, Content-Type, Accept, header parameters, then
+//"x-postman-headers" extensions.
+//An operation header overrides a global header with the same name, at its position.
+//Optional header parameters are disabled.
 func (c *Converter) buildPostmanHeaders(operation *spec.Operation) []postman2.Header {
+
+	var returnHeader []postman2.Header
+	positions := make(map[string]int)
+
+	setHeader := func(header postman2.Header) {
+		key := strings.ToLower(header.Key)
+		if i, ok := positions[key]; ok {
+			returnHeader[i] = header
+			return
+		}
+		positions[key] = len(returnHeader)
+		returnHeader = append(returnHeader, header)
+	}
+
+	keys := []string{}
+	for key := range c.config.PostmanHeaders {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		header := c.config.PostmanHeaders[key]
+		if header.Key == "" {
+			header.Key = key
+		}
+		setHeader(header)
+	}
+
 	if len(operation.Consumes) > 0 {
 		if len(strings.TrimSpace(operation.Consumes[0])) > 0 {
-			c.config.PostmanHeaders["Content-Type"] = postman2.Header{
+			setHeader(postman2.Header{
 				Key:   "Content-Type",
-				Value: strings.TrimSpace(operation.Consumes[0])}
+				Value: strings.TrimSpace(operation.Consumes[0])})
 		}
 	}
 	if len(operation.Produces) > 0 {
 		if len(strings.TrimSpace(operation.Produces[0])) > 0 {
-			c.config.PostmanHeaders["Accept"] = postman2.Header{
+			setHeader(postman2.Header{
 				Key:   "Accept",
-				Value: strings.TrimSpace(operation.Produces[0])}
+				Value: strings.TrimSpace(operation.Produces[0])})
 		}
 	}
 
-	for _, param := range operation.Parameters {
+	for i, param := range operation.Parameters {
 		if param.In == "header" {
 			var value string
-			if param.Default != nil {
-				value, _ = param.Default.(string)
+			if v, ok := postmanValue(param.Extensions, param.Type); ok {
+				value = fmt.Sprint(plainValue(v))
+			} else if param.Default != nil {
+				value = c.stringValue(param.Default, appendPointer(c.parameterLocation(i), "default"))
 			} else if param.Example != nil {
-				value, _ = param.Example.(string)
+				value = c.stringValue(param.Example, appendPointer(c.parameterLocation(i), "example"))
 			} else {
-				value = "string"
+				value = c.generateString(param.Name, param.Type, param.Format)
 			}
 
-			c.config.PostmanHeaders[param.Name] = postman2.Header{
-				Key:   param.Name,
-				Value: value,
-			}
+			setHeader(postman2.Header{
+				Key:         param.Name,
+				Value:       value,
+				Description: param.Description,
+				Disabled:    !param.Required,
+			})
 		}
 	}
 
-	var returnHeader []postman2.Header
-
-	for _, header := range c.config.PostmanHeaders {
-		returnHeader = append(returnHeader, header)
+	for _, header := range c.postmanHeaders(operation) {
+		setHeader(header)
 	}
 
 	return returnHeader
 
 }
 
+const (
+	mediaTypeURLEncoded  = "application/x-www-form-urlencoded"
+	mediaTypeMultipart   = "multipart/form-data"
+	mediaTypeJSON        = "application/json"
+	mediaTypeOctetStream = "application/octet-stream"
+
+	postmanFileExtension = "x-postman-file"
+)
+
 //buildPostmanBody builds a request body from swagger Operation
-//Implementation is done for formData type and raw body type.
+//Implementation is done for formData type, x-www-form-urlencoded type and raw body type.
+//The body mode is chosen from the media types consumed by the operation, unless a BodyMode is configured.
 func (c *Converter) buildPostmanBody(operation *spec.Operation) postman2.RequestBody {
 
 	requestBody := postman2.RequestBody{}
 
+	formMediaType := c.formMediaType(operation)
+	urlEncoded := formMediaType == mediaTypeURLEncoded
+	rawMediaType := consumedRawMediaType(operation.Consumes)
+
 	var formData []postman2.FormData
 
-	for _, param := range operation.Parameters {
+	for i, param := range operation.Parameters {
+
+		location := c.parameterLocation(i)
+
+		//file upload
+		if param.In == "formData" && param.Type == "file" {
+			formData = append(formData, postman2.FormData{
+				Key:         param.Name,
+				Src:         c.fileSource(param.Name, param.Extensions),
+				Description: param.Description,
+				Enabled:     param.Required,
+				Disabled:    !param.Required,
+				Type:        "file",
+			})
+			continue
+		}
 
 		//formData
 		if param.In == "formData" {
 			var value string
-			if param.Default != nil {
-				value, _ = param.Default.(string)
+			if v, ok := postmanValue(param.Extensions, param.Type); ok {
+				value = fmt.Sprint(plainValue(v))
+			} else if param.Default != nil {
+				value = c.stringValue(param.Default, appendPointer(location, "default"))
 			} else if param.Example != nil {
-				value, _ = param.Example.(string)
+				value = c.stringValue(param.Example, appendPointer(location, "example"))
 			} else {
-				value = "string"
+				value = c.generateString(param.Name, param.Type, param.Format)
 			}
 
 			formData = append(formData, postman2.FormData{
-				Key:     param.Name,
-				Value:   value,
-				Enabled: param.Required,
-				Type:    "text",
+				Key:         param.Name,
+				Value:       value,
+				Description: param.Description,
+				Enabled:     param.Required,
+				Disabled:    !param.Required,
+				Type:        "text",
 			})
 		}
 
-		//raw body
-		if param.Required && param.In == "body" {
-			if param.Schema.Type.Contains("object") {
-				props := c.buildProperties(param.Schema.Properties)
-				requestBody.Raw = props
-			}
+		//urlencoded or multipart body, from the schema properties
+		if formMediaType != "" && param.In == "body" && param.Schema != nil {
+			formData = append(formData, c.buildFormProperties(*param.Schema, appendPointer(location, "schema"))...)
+			continue
+		}
 
-			if param.Schema.Type.Contains("array") {
-				if param.Schema.Items.ContainsType("object") {
-					array := []json.RawMessage{json.RawMessage(c.buildProperties(param.Schema.Items.Schema.Properties))}
-					rawArray, _ := json.MarshalIndent(array, "", "\t")
-					requestBody.Raw = string(rawArray)
-					continue
-				}
+		//binary body
+		if param.In == "body" && param.Schema != nil && (rawMediaType == mediaTypeOctetStream || param.Schema.Format == "binary") {
+			requestBody.Mode = "file"
+			requestBody.File = &postman2.BodyFile{Src: c.fileSource(param.Name, param.Extensions)}
+			return requestBody
+		}
 
-				var array []interface{}
-				array = append(array, buildPropertyDefaultValue(param.Schema.Items.Schema.Type, param.Schema.Items.Schema.Format))
-				rawArray, _ := json.MarshalIndent(array, "", "\t")
-				requestBody.Raw = string(rawArray)
+		//raw body, whatever its type and whether it is required or not
+		if param.In == "body" && param.Schema != nil {
+			raw, err := c.serializeBody(rawMediaType, c.bodyDefinitions[location], *param.Schema, c.buildSchemaValue(*param.Schema, newSchemaTrail(appendPointer(location, "schema"))))
+			if err != nil {
+				c.reportError(SeverityError, CodeEncodingFailed, location, &SchemaError{Location: location, Err: err}, "unable to encode body of %s as %s: %s", param.Name, rawMediaType, err)
+			}
+			requestBody.Raw = raw
+		}
+	}
 
+	if urlEncoded {
+		for _, data := range formData {
+			if data.Type == "file" {
+				c.report(SeverityWarning, CodeUnsupportedParameter, c.location, "file parameter %s can not be sent as x-www-form-urlencoded", data.Key)
+				continue
 			}
+			requestBody.URLEncoded = append(requestBody.URLEncoded, postman2.URLEncodedParam{
+				Key:         data.Key,
+				Value:       data.Value,
+				Description: data.Description,
+				Enabled:     data.Enabled,
+				Disabled:    data.Disabled,
+				Type:        data.Type,
+			})
 		}
+		requestBody.Mode = "urlencoded"
+		return requestBody
 	}
 
 	if len(formData) > 0 {
@@ -147,7 +513,161 @@ func (c *Converter) buildPostmanBody(operation *spec.Operation) postman2.Request
 	}
 
 	requestBody.Mode = "raw"
+	requestBody.Options = &postman2.BodyOptions{
+		Raw: postman2.RawOptions{Language: rawLanguage(rawMediaType)},
+	}
 
-	//TODO: Add other kind of body binary? x-www-form-urlencode?
 	return requestBody
 }
+
+//serializeBody encodes a generated body according to a media type : xml, plain text or json by default.
+//Xml bodies are named after the definition of their schema, if any.
+func (c *Converter) serializeBody(mediaType, definition string, schema spec.Schema, value interface{}) (string, error) {
+
+	switch rawLanguage(mediaType) {
+	case "xml":
+		return c.buildXML(definition, schema, value)
+	case "text":
+		if s, ok := plainValue(value).(string); ok {
+			return s, nil
+		}
+	}
+
+	raw, err := marshalIndent(value)
+	return string(raw), err
+}
+
+//buildFormProperties builds form fields from the properties of a body schema, located at the given JSON pointer.
+//Nested objects and arrays are encoded as json, and binary properties are sent as files.
+func (c *Converter) buildFormProperties(schema spec.Schema, location string) []postman2.FormData {
+
+	object, ok := c.buildSchemaValue(schema, newSchemaTrail(location)).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	resolved := c.resolveSchema(schema)
+	required := make(map[string]bool)
+	for _, name := range resolved.Required {
+		required[name] = true
+	}
+
+	keys := []string{}
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var formData []postman2.FormData
+
+	for _, key := range keys {
+		prop := c.resolveSchema(resolved.Properties[key])
+		if prop.Format == "binary" {
+			formData = append(formData, postman2.FormData{
+				Key:         key,
+				Src:         c.fileSource(key, prop.Extensions),
+				Description: prop.Description,
+				Enabled:     required[key],
+				Disabled:    !required[key],
+				Type:        "file",
+			})
+			continue
+		}
+
+		formData = append(formData, postman2.FormData{
+			Key:         key,
+			Value:       c.formValue(object[key], appendPointer(location, "properties", key)),
+			Description: prop.Description,
+			Enabled:     required[key],
+			Disabled:    !required[key],
+			Type:        "text",
+		})
+	}
+
+	return formData
+}
+
+//fileSource builds the source path of a file to upload, from a "x-postman-file" swagger extension or from the
+//field name, relative to the fixtures directory defined in the config.
+func (c *Converter) fileSource(name string, extensions spec.Extensions) string {
+	if file, ok := extensions.GetString(postmanFileExtension); ok && strings.TrimSpace(file) != "" {
+		name = strings.TrimSpace(file)
+	}
+
+	if c.config.FixturesDir == "" || path.IsAbs(name) {
+		return name
+	}
+
+	return path.Join(c.config.FixturesDir, name)
+}
+
+//stringValue renders a default or example value as a string.
+//Values which are not strings are formatted as is, and reported.
+func (c *Converter) stringValue(v interface{}, location string) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	c.report(SeverityInfo, CodeInvalidValue, location, "value %v is not a string and is formatted as is", v)
+	return fmt.Sprint(v)
+}
+
+//formValue renders a generated value as a form field value. Objects and arrays are encoded as json.
+//A value which can not be encoded is reported at the location of its field, and rendered empty.
+func (c *Converter) formValue(v interface{}, location string) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case dynamicVariable:
+		return string(value)
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(markDynamicVariables(value))
+		if err != nil {
+			c.reportError(SeverityError, CodeEncodingFailed, location, &SchemaError{Location: location, Err: err}, "unable to encode form field: %s", err)
+			return ""
+		}
+		return string(dynamicVariableMarker.ReplaceAll(b, []byte("$1")))
+	}
+	return fmt.Sprint(v)
+}
+
+//consumedRawMediaType returns the first media type consumed by an operation which is not a form media type.
+//Default is application/json.
+func consumedRawMediaType(consumes []string) string {
+	for _, mediaType := range consumes {
+		switch mediaType = strings.ToLower(strings.TrimSpace(mediaType)); mediaType {
+		case mediaTypeURLEncoded, mediaTypeMultipart, "":
+			continue
+		default:
+			return mediaType
+		}
+	}
+	return mediaTypeJSON
+}
+
+//rawLanguage returns the Postman raw body language matching a media type
+func rawLanguage(mediaType string) string {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return "json"
+	case strings.HasSuffix(mediaType, "xml"):
+		return "xml"
+	case mediaType == "text/html":
+		return "html"
+	case strings.HasSuffix(mediaType, "javascript"):
+		return "javascript"
+	}
+	return "text"
+}
+
+//consumedFormMediaType returns the first form media type (x-www-form-urlencoded or multipart/form-data)
+//consumed by an operation, if any
+func consumedFormMediaType(consumes []string) string {
+	for _, mediaType := range consumes {
+		switch mediaType = strings.ToLower(strings.TrimSpace(mediaType)); mediaType {
+		case mediaTypeURLEncoded, mediaTypeMultipart:
+			return mediaType
+		}
+	}
+	return ""
+}
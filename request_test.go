@@ -1,6 +1,10 @@
 package postmanify
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
 	"testing"
 
 	"github.com/seblegall/postmanify/postman2"
@@ -258,3 +262,606 @@ func TestBuildPostmanHeaders(t *testing.T) {
 	}
 
 }
+
+func TestBuildPostmanItems(t *testing.T) {
+
+	operation := &spec.Operation{
+		VendorExtensible: spec.VendorExtensible{
+			Extensions: spec.Extensions{
+				"x-examples": map[string]interface{}{
+					"valid_user": map[string]interface{}{
+						"summary": "A valid user",
+						"value":   map[string]interface{}{"name": "john"},
+					},
+					"admin_user": map[string]interface{}{
+						"summary": "An admin user",
+						"value":   map[string]interface{}{"name": "admin"},
+					},
+				},
+			},
+		},
+		OperationProps: spec.OperationProps{
+			Parameters: []spec.Parameter{
+				{
+					ParamProps: spec.ParamProps{
+						In:       "body",
+						Required: true,
+						Schema: &spec.Schema{
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"object"},
+								Properties: map[string]spec.Schema{
+									"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	conv := NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}})
+
+	items := conv.buildPostmanItems("/users", http.MethodPost, operation)
+
+	assert.Len(t, items, 2)
+	assert.Equal(t, "/users (admin_user)", items[0].Name)
+	assert.Equal(t, "An admin user", items[0].Request.Description)
+	assert.Equal(t, indentJSON(`{"name":"admin"}`), items[0].Request.Body.Raw)
+	assert.Equal(t, "/users (valid_user)", items[1].Name)
+	assert.Equal(t, "A valid user", items[1].Request.Description)
+	assert.Equal(t, indentJSON(`{"name":"john"}`), items[1].Request.Body.Raw)
+
+	items = conv.buildPostmanItems("/users", http.MethodPost, &spec.Operation{})
+
+	assert.Len(t, items, 1)
+	assert.Equal(t, "/users", items[0].Name)
+}
+
+func TestBuildPostmanURLEncoded(t *testing.T) {
+	dataset := []struct {
+		input    *spec.Operation
+		expected []postman2.URLEncodedParam
+	}{
+		{
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Consumes: []string{"application/x-www-form-urlencoded"},
+					Parameters: []spec.Parameter{
+						{
+							ParamProps:   spec.ParamProps{In: "formData", Required: true, Name: "grant_type"},
+							SimpleSchema: spec.SimpleSchema{Type: "string", Default: "password"},
+						},
+						{
+							ParamProps:   spec.ParamProps{In: "formData", Name: "scope"},
+							SimpleSchema: spec.SimpleSchema{Type: "string"},
+						},
+					},
+				},
+			},
+			expected: []postman2.URLEncodedParam{
+				{Key: "grant_type", Value: "password", Enabled: true, Type: "text"},
+				{Key: "scope", Value: "string", Enabled: false, Disabled: true, Type: "text"},
+			},
+		},
+		{
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Consumes: []string{"application/x-www-form-urlencoded"},
+					Parameters: []spec.Parameter{
+						{
+							ParamProps: spec.ParamProps{
+								In:   "body",
+								Name: "body",
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:     spec.StringOrArray{"object"},
+										Required: []string{"username"},
+										Properties: map[string]spec.Schema{
+											"username": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+											"tags": {SchemaProps: spec.SchemaProps{
+												Type:  spec.StringOrArray{"array"},
+												Items: &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}}}},
+											}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []postman2.URLEncodedParam{
+				{Key: "tags", Value: "[0]", Enabled: false, Disabled: true, Type: "text"},
+				{Key: "username", Value: "string", Enabled: true, Type: "text"},
+			},
+		},
+	}
+
+	for _, data := range dataset {
+
+		conv := NewConverter(Config{})
+
+		requestBody := conv.buildPostmanBody(data.input)
+
+		assert.Equal(t, "urlencoded", requestBody.Mode)
+		assert.Equal(t, data.expected, requestBody.URLEncoded)
+		assert.Empty(t, requestBody.FormData)
+	}
+
+	//multipart operations keep the formdata mode
+	requestBody := NewConverter(Config{}).buildPostmanBody(&spec.Operation{
+		OperationProps: spec.OperationProps{
+			Consumes: []string{"multipart/form-data", "application/x-www-form-urlencoded"},
+			Parameters: []spec.Parameter{
+				{
+					ParamProps:   spec.ParamProps{In: "formData", Name: "file"},
+					SimpleSchema: spec.SimpleSchema{Type: "string"},
+				},
+			},
+		},
+	})
+	assert.Equal(t, "formdata", requestBody.Mode)
+}
+
+func TestBuildPostmanFileUpload(t *testing.T) {
+	dataset := []struct {
+		config   Config
+		input    *spec.Operation
+		expected []postman2.FormData
+	}{
+		{
+			config: Config{},
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Consumes: []string{"multipart/form-data"},
+					Parameters: []spec.Parameter{
+						{
+							ParamProps:   spec.ParamProps{In: "formData", Required: true, Name: "avatar"},
+							SimpleSchema: spec.SimpleSchema{Type: "file"},
+						},
+						{
+							ParamProps:   spec.ParamProps{In: "formData", Required: true, Name: "title"},
+							SimpleSchema: spec.SimpleSchema{Type: "string"},
+						},
+					},
+				},
+			},
+			expected: []postman2.FormData{
+				{Key: "avatar", Src: "avatar", Enabled: true, Type: "file"},
+				{Key: "title", Value: "string", Enabled: true, Type: "text"},
+			},
+		},
+		{
+			config: Config{FixturesDir: "fixtures"},
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Consumes: []string{"multipart/form-data"},
+					Parameters: []spec.Parameter{
+						{
+							ParamProps:       spec.ParamProps{In: "formData", Name: "avatar"},
+							SimpleSchema:     spec.SimpleSchema{Type: "file"},
+							VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-postman-file": "images/avatar.png"}},
+						},
+					},
+				},
+			},
+			expected: []postman2.FormData{
+				{Key: "avatar", Src: "fixtures/images/avatar.png", Enabled: false, Disabled: true, Type: "file"},
+			},
+		},
+		{
+			config: Config{FixturesDir: "fixtures"},
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Consumes: []string{"multipart/form-data"},
+					Parameters: []spec.Parameter{
+						{
+							ParamProps: spec.ParamProps{
+								In:   "body",
+								Name: "body",
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:     spec.StringOrArray{"object"},
+										Required: []string{"document"},
+										Properties: map[string]spec.Schema{
+											"document": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Format: "binary"}},
+											"name":     {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []postman2.FormData{
+				{Key: "document", Src: "fixtures/document", Enabled: true, Type: "file"},
+				{Key: "name", Value: "string", Enabled: false, Disabled: true, Type: "text"},
+			},
+		},
+	}
+
+	for _, data := range dataset {
+
+		conv := NewConverter(data.config)
+
+		requestBody := conv.buildPostmanBody(data.input)
+
+		assert.Equal(t, "formdata", requestBody.Mode)
+		assert.Equal(t, data.expected, requestBody.FormData)
+	}
+}
+
+func TestBuildPostmanRawBody(t *testing.T) {
+
+	userSchema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+			},
+		},
+	}
+
+	dataset := []struct {
+		consumes []string
+		schema   *spec.Schema
+		expected postman2.RequestBody
+	}{
+		{
+			consumes: nil,
+			schema:   userSchema,
+			expected: postman2.RequestBody{
+				Mode:    "raw",
+				Raw:     indentJSON(`{"name":"string"}`),
+				Options: &postman2.BodyOptions{Raw: postman2.RawOptions{Language: "json"}},
+			},
+		},
+		{
+			consumes: []string{"application/xml"},
+			schema:   userSchema,
+			expected: postman2.RequestBody{
+				Mode:    "raw",
+				Raw:     "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<root>\n\t<name>string</name>\n</root>",
+				Options: &postman2.BodyOptions{Raw: postman2.RawOptions{Language: "xml"}},
+			},
+		},
+		{
+			consumes: []string{"text/plain"},
+			schema:   &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"array"}, Items: &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}}}}},
+			expected: postman2.RequestBody{
+				Mode:    "raw",
+				Raw:     indentJSON(`["string"]`),
+				Options: &postman2.BodyOptions{Raw: postman2.RawOptions{Language: "text"}},
+			},
+		},
+		{
+			consumes: []string{"application/octet-stream"},
+			schema:   &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Format: "binary"}},
+			expected: postman2.RequestBody{
+				Mode: "file",
+				File: &postman2.BodyFile{Src: "fixtures/body"},
+			},
+		},
+	}
+
+	for _, data := range dataset {
+
+		conv := NewConverter(Config{FixturesDir: "fixtures"})
+
+		requestBody := conv.buildPostmanBody(&spec.Operation{
+			OperationProps: spec.OperationProps{
+				Consumes: data.consumes,
+				Parameters: []spec.Parameter{
+					{ParamProps: spec.ParamProps{In: "body", Name: "body", Required: true, Schema: data.schema}},
+				},
+			},
+		})
+
+		assert.Equal(t, data.expected, requestBody)
+	}
+}
+
+func TestBuildPostmanOptionalBody(t *testing.T) {
+
+	dataset := []struct {
+		method   string
+		input    *spec.Operation
+		expected string
+	}{
+		{
+			method: http.MethodPost,
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Parameters: []spec.Parameter{
+						{ParamProps: spec.ParamProps{In: "body", Name: "body", Schema: &spec.Schema{
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"object"},
+								Properties: map[string]spec.Schema{
+									"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+								},
+							},
+						}}},
+					},
+				},
+			},
+			expected: indentJSON(`{"name":"string"}`),
+		},
+		{
+			method: http.MethodPut,
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Parameters: []spec.Parameter{
+						{ParamProps: spec.ParamProps{In: "body", Name: "body", Required: true, Schema: &spec.Schema{
+							SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}},
+						}}},
+					},
+				},
+			},
+			expected: `"string"`,
+		},
+		{
+			method: http.MethodDelete,
+			input: &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Parameters: []spec.Parameter{
+						{ParamProps: spec.ParamProps{In: "body", Name: "body", Required: true, Schema: &spec.Schema{
+							SchemaProps: spec.SchemaProps{
+								Type:  spec.StringOrArray{"array"},
+								Items: &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}}}},
+							},
+						}}},
+					},
+				},
+			},
+			expected: indentJSON(`[0]`),
+		},
+	}
+
+	for _, data := range dataset {
+
+		conv := NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}})
+
+		item := conv.buildPostmanItem("/test", data.method, data.input)
+
+		assert.Equal(t, "raw", item.Request.Body.Mode)
+		assert.Equal(t, data.expected, item.Request.Body.Raw)
+	}
+
+	//operations without any documented body have no body
+	item := NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}}).buildPostmanItem("/test", http.MethodGet, &spec.Operation{})
+	assert.Empty(t, item.Request.Body.Mode)
+}
+
+func TestBuildPostmanContentVariants(t *testing.T) {
+
+	operation := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Produces: []string{"application/json", "text/csv"},
+			Parameters: []spec.Parameter{
+				{ParamProps: spec.ParamProps{In: "body", Name: "body", Required: true, Schema: &spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+						},
+					},
+				}}},
+			},
+		},
+	}
+
+	header := func(item postman2.APIItem, key string) string {
+		for _, h := range item.Request.Header {
+			if h.Key == key {
+				return h.Value
+			}
+		}
+		return ""
+	}
+
+	conv := NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}, ExpandContentTypes: true})
+	conv.consumes = []string{"application/json", "application/xml"}
+
+	items := conv.buildPostmanItems("/users", http.MethodPost, operation)
+
+	assert.Len(t, items, 4)
+	assert.Equal(t, "/users (Content-Type: application/json, Accept: application/json)", items[0].Name)
+	assert.Equal(t, "application/json", header(items[0], "Content-Type"))
+	assert.Equal(t, "application/json", header(items[0], "Accept"))
+	assert.Equal(t, indentJSON(`{"name":"string"}`), items[0].Request.Body.Raw)
+	assert.Equal(t, "/users (Content-Type: application/json, Accept: text/csv)", items[1].Name)
+	assert.Equal(t, "text/csv", header(items[1], "Accept"))
+	assert.Equal(t, "/users (Content-Type: application/xml, Accept: application/json)", items[2].Name)
+	assert.Equal(t, "application/xml", header(items[2], "Content-Type"))
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<root>\n\t<name>string</name>\n</root>", items[2].Request.Body.Raw)
+	assert.Equal(t, "xml", items[2].Request.Body.Options.Raw.Language)
+
+	//without expansion, the root-level media types are used when the operation has none
+	conv = NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}})
+	conv.consumes = []string{"application/xml"}
+
+	items = conv.buildPostmanItems("/users", http.MethodPost, operation)
+
+	assert.Len(t, items, 1)
+	assert.Equal(t, "/users", items[0].Name)
+	assert.Equal(t, "application/xml", header(items[0], "Content-Type"))
+	assert.Equal(t, "application/json", header(items[0], "Accept"))
+
+	//operations without a body are only expanded over their produced media types, without any Content-Type
+	conv = NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}, ExpandContentTypes: true})
+	conv.consumes = []string{"application/json", "application/xml"}
+
+	items = conv.buildPostmanItems("/users/{id}", http.MethodGet, &spec.Operation{})
+
+	assert.Len(t, items, 1)
+	assert.Equal(t, "/users/{id}", items[0].Name)
+	assert.Empty(t, items[0].Request.Header)
+
+	items = conv.buildPostmanItems("/users/{id}", http.MethodGet, &spec.Operation{OperationProps: spec.OperationProps{
+		Consumes: []string{"application/json", "application/xml"},
+		Produces: []string{"application/json", "text/csv"},
+	}})
+
+	assert.Len(t, items, 2)
+	assert.Equal(t, "/users/{id} (Accept: application/json)", items[0].Name)
+	assert.Equal(t, "/users/{id} (Accept: text/csv)", items[1].Name)
+	assert.Equal(t, "text/csv", header(items[1], "Accept"))
+}
+
+func TestBuildPostmanHeadersIsolation(t *testing.T) {
+
+	global := map[string]postman2.Header{
+		"Authorization": {Key: "Authorization", Value: "Bearer {{my_access_token}}"},
+		"X-Client":      {Key: "X-Client", Value: "postman"},
+	}
+
+	conv := NewConverter(Config{PostmanHeaders: global})
+
+	first := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Consumes: []string{"application/xml"},
+			Produces: []string{"application/json"},
+			Parameters: []spec.Parameter{
+				{
+					ParamProps:   spec.ParamProps{In: "header", Name: "X-Tenant"},
+					SimpleSchema: spec.SimpleSchema{Type: "string", Default: "acme"},
+				},
+				{
+					ParamProps:   spec.ParamProps{In: "header", Name: "x-client"},
+					SimpleSchema: spec.SimpleSchema{Type: "string", Default: "cli"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []postman2.Header{
+		{Key: "Authorization", Value: "Bearer {{my_access_token}}"},
+		{Key: "x-client", Value: "cli", Disabled: true},
+		{Key: "Content-Type", Value: "application/xml"},
+		{Key: "Accept", Value: "application/json"},
+		{Key: "X-Tenant", Value: "acme", Disabled: true},
+	}, conv.buildPostmanHeaders(first))
+
+	//headers of the first operation do not leak into the second one
+	assert.Equal(t, []postman2.Header{
+		{Key: "Authorization", Value: "Bearer {{my_access_token}}"},
+		{Key: "X-Client", Value: "postman"},
+	}, conv.buildPostmanHeaders(&spec.Operation{}))
+
+	//global headers are left untouched
+	assert.Equal(t, map[string]postman2.Header{
+		"Authorization": {Key: "Authorization", Value: "Bearer {{my_access_token}}"},
+		"X-Client":      {Key: "X-Client", Value: "postman"},
+	}, global)
+}
+
+func TestConvertPathParameters(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "path parameters", "version": "1.0"},
+		"host": "api.example.com",
+		"parameters": {
+			"tenantId": {"in": "path", "name": "tenantId", "type": "string", "required": true, "default": "acme"}
+		},
+		"paths": {
+			"/tenants/{tenantId}/users": {
+				"parameters": [
+					{"$ref": "#/parameters/tenantId"},
+					{"in": "header", "name": "X-Tenant", "type": "string", "default": "path"},
+					{"in": "header", "name": "X-Trace", "type": "string", "default": 42}
+				],
+				"get": {
+					"tags": ["users"],
+					"parameters": [
+						{"in": "header", "name": "X-Tenant", "type": "string", "default": "operation"},
+						{"in": "query", "name": "X-Trace", "type": "string", "default": "query"},
+						{"in": "query", "name": "tenantId", "type": "string", "default": "query"}
+					]
+				}
+			}
+		}
+	}`)
+
+	out, report, err := NewConverter(Config{}).Convert(swag)
+	assert.NoError(t, err)
+
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(out, &collection))
+
+	request := collection.Item[0].Item[0].Request
+	assert.Equal(t, []postman2.URLVariable{{ID: "tenantId", Value: "acme"}}, request.URL.Variable)
+	assert.Equal(t, []postman2.Header{
+		{Key: "X-Tenant", Value: "operation", Disabled: true},
+		{Key: "X-Trace", Value: "42", Disabled: true},
+	}, request.Header)
+	assert.Equal(t, "X-Trace", request.URL.Query[0].Key)
+	assert.Equal(t, "query", request.URL.Query[0].Value)
+	//a query parameter named after a path variable does not set its value
+	assert.Equal(t, "tenantId", request.URL.Query[1].Key)
+	assert.Equal(t, "query", request.URL.Query[1].Value)
+
+	//diagnostics on inherited parameters point to the path item
+	assert.Equal(t, []Diagnostic{
+		{Code: CodeInvalidValue, Severity: SeverityInfo, Location: "#/paths/~1tenants~1{tenantId}~1users/parameters/2/default", Message: "value 42 is not a string and is formatted as is"},
+	}, report.Diagnostics)
+}
+
+func TestBuildFormPropertiesEncodingFailed(t *testing.T) {
+
+	conv := NewConverter(Config{ValueGenerator: ValueGeneratorFunc(func(name, propType, format string) interface{} {
+		if propType == "number" {
+			return math.NaN()
+		}
+		return "value"
+	})})
+
+	formData := conv.buildFormProperties(spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: spec.StringOrArray{"object"},
+		Properties: map[string]spec.Schema{
+			"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+			"position": {SchemaProps: spec.SchemaProps{
+				Type:       spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{"latitude": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"number"}}}},
+			}},
+		},
+	}}, "#/paths/~1users/post/parameters/0/schema")
+
+	assert.Equal(t, []postman2.FormData{
+		{Key: "name", Value: "value", Disabled: true, Type: "text"},
+		{Key: "position", Value: "", Disabled: true, Type: "text"},
+	}, formData)
+
+	assert.Len(t, conv.diagnostics.Diagnostics, 1)
+	diagnostic := conv.diagnostics.Diagnostics[0]
+	assert.Equal(t, CodeEncodingFailed, diagnostic.Code)
+	assert.Equal(t, SeverityError, diagnostic.Severity)
+	assert.Equal(t, "#/paths/~1users/post/parameters/0/schema/properties/position", diagnostic.Location)
+
+	var schemaErr *SchemaError
+	assert.True(t, errors.As(diagnostic.Err, &schemaErr))
+}
+
+func TestBuildPostmanContentVariantsNamedExamples(t *testing.T) {
+
+	var operation spec.Operation
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"consumes": ["application/json", "application/xml"],
+		"x-examples": {"full": {"value": {"name": "john"}}},
+		"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}]
+	}`), &operation))
+
+	conv := NewConverter(Config{PostmanHeaders: map[string]postman2.Header{}, ExpandContentTypes: true})
+	items := conv.buildPostmanItems("/cats/{id}", http.MethodPost, &operation)
+
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	assert.Equal(t, []string{
+		"/cats/{id} (full, Content-Type: application/json)",
+		"/cats/{id} (full, Content-Type: application/xml)",
+	}, names)
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/go-openapi/spec"
 )
 
-func TestbuildPostmanScript(t *testing.T) {
+func TestBuildPostmanScript(t *testing.T) {
 
 	var script []interface{}
 	script = append(script, "test")
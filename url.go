@@ -1,18 +1,19 @@
 package postmanify
 
 import (
+	"context"
+	"fmt"
 	"net/http"
-	"regexp"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/seblegall/postmanify/postman2"
 	"github.com/go-openapi/spec"
 )
 
-//addUrls add a postman items for each swagger path in the spec
-func (c *Converter) addUrls(paths map[string]spec.PathItem, pman *postman2.Collection) error {
+//addUrls add a postman items for each swagger path in the spec, until the context is done.
+//Operations not selected by the filter defined in the config are skipped.
+func (c *Converter) addUrls(ctx context.Context, paths map[string]spec.PathItem, pman *postman2.Collection) error {
 	urls := []string{}
 	for url := range paths {
 		urls = append(urls, url)
@@ -20,127 +21,173 @@ func (c *Converter) addUrls(paths map[string]spec.PathItem, pman *postman2.Colle
 	sort.Strings(urls)
 
 	for _, url := range urls {
-		path := paths[url]
-
-		if pathHasMethodWithTag(path, http.MethodGet) {
-			pman.AddItem(c.buildPostmanItem(url, http.MethodGet, path.Get), strings.TrimSpace(path.Get.Tags[0]))
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-		if pathHasMethodWithTag(path, http.MethodPatch) {
-			pman.AddItem(c.buildPostmanItem(url, http.MethodPatch, path.Patch), strings.TrimSpace(path.Patch.Tags[0]))
+
+		path := paths[url]
+		c.pathExtensions = path.Extensions
+
+		operations := []struct {
+			method    string
+			operation *spec.Operation
+		}{
+			{http.MethodGet, path.Get},
+			{http.MethodPatch, path.Patch},
+			{http.MethodPost, path.Post},
+			{http.MethodPut, path.Put},
+			{http.MethodDelete, path.Delete},
 		}
-		if pathHasMethodWithTag(path, http.MethodPost) {
-			pman.AddItem(c.buildPostmanItem(url, http.MethodPost, path.Post), strings.TrimSpace(path.Post.Tags[0]))
+
+		for _, op := range operations {
+			if op.operation == nil || !c.selected(url, op.method, op.operation) {
+				continue
+			}
+			c.location = jsonPointer("paths", url, strings.ToLower(op.method))
+			if c.skipped(op.operation) {
+				continue
+			}
+			folder := c.folderName(url, op.operation)
+			if folder == "" {
+				c.report(SeverityWarning, CodeUntaggedOperation, c.location, "operation has no tag and is not converted")
+				continue
+			}
+			operation := c.withPathParameters(url, path, op.operation)
+			for _, item := range c.buildPostmanItems(url, op.method, operation) {
+				if err := c.transformItem(&item, url, op.method, operation); err != nil {
+					return err
+				}
+				pman.AddItem(item, folder)
+			}
 		}
-		if pathHasMethodWithTag(path, http.MethodPut) {
-			pman.AddItem(c.buildPostmanItem(url, http.MethodPut, path.Put), strings.TrimSpace(path.Put.Tags[0]))
+
+		//HEAD and OPTIONS operations are not converted
+		unsupported := []struct {
+			method    string
+			operation *spec.Operation
+		}{
+			{http.MethodHead, path.Head},
+			{http.MethodOptions, path.Options},
 		}
-		if pathHasMethodWithTag(path, http.MethodDelete) {
-			pman.AddItem(c.buildPostmanItem(url, http.MethodDelete, path.Delete), strings.TrimSpace(path.Delete.Tags[0]))
+
+		for _, op := range unsupported {
+			if op.operation != nil && c.selected(url, op.method, op.operation) {
+				c.report(SeverityWarning, CodeUnsupportedOperation, jsonPointer("paths", url, strings.ToLower(op.method)), "%s operations are not converted", op.method)
+			}
 		}
 	}
 
+	c.location = ""
+	c.parameterLocations = nil
+	c.pathExtensions = nil
+
 	return nil
 }
 
-//buildPostmanURL build a postman url, part of a postman item, from a swagger operation
+//buildPostmanURL build a postman url, part of a postman item, from a swagger operation.
+//Path templates, such as {id}, become postman variables : {{id}}. The hostname may be templated too, such as {region}.api.com.
 func (c *Converter) buildPostmanURL(url string, operation *spec.Operation) postman2.URL {
 
-	//create hostname
-	host := strings.TrimSpace(strings.Join([]string{
-		c.config.Hostname,
-	}, ""))
-
-	//create URI
-	rawPostmanURL := strings.TrimSpace(strings.Join([]string{
-		host,
-		c.config.BasePath,
-		strings.Replace(url, " ", "", -1),
-	}, "/"))
+	host, _ := templateURL(c.config.Hostname)
+	path, variables := templateURL(strings.Join([]string{c.config.BasePath, url}, "/"))
 
-	rx1 := regexp.MustCompile(`/+`)
-	rawPostmanURL = rx1.ReplaceAllString(rawPostmanURL, "/")
-	rx2 := regexp.MustCompile(`^/+`)
-	rawPostmanURL = rx2.ReplaceAllString(rawPostmanURL, "")
+	//create URI, without duplicated slashes
+	rawPostmanURL := host
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			rawPostmanURL += "/" + segment
+		}
+	}
+	rawPostmanURL = strings.TrimLeft(rawPostmanURL, "/")
 
 	//Add schema
-	rawPostmanURL = strings.Join([]string{c.config.Schema, rawPostmanURL}, "://")
-
-	//Replace URI parameters
-	rx3 := regexp.MustCompile(`(^|[^\{])\{([^\/\{\}]+)\}([^\}]|$)`)
-	rawPostmanURL = rx3.ReplaceAllString(rawPostmanURL, "$1{{$2}}$3")
+	if c.config.Schema != "" {
+		rawPostmanURL = c.config.Schema + "://" + rawPostmanURL
+	}
 
 	postmanURL := postman2.NewURL(rawPostmanURL)
 
 	// Set Default URL Path Parameters
-	rx4 := regexp.MustCompile(`^\s*({{(.+)}})\s*$`)
-
-	for _, part := range postmanURL.Path {
-		rs4 := rx4.FindAllStringSubmatch(part, -1)
-		if len(rs4) > 0 {
-			baseVariable := rs4[0][2]
-			var defaultValue interface{}
-			for _, parameter := range operation.Parameters {
-				if parameter.Name == baseVariable {
-					defaultValue = parameter.Default
-					break
-				}
+	for _, variable := range variables {
+		var defaultValue interface{}
+		for _, parameter := range operation.Parameters {
+			if parameter.In == "path" && parameter.Name == variable {
+				defaultValue = parameter.Default
+				break
 			}
-			postmanURL.AddVariable(baseVariable, defaultValue)
 		}
+		postmanURL.AddVariable(variable, defaultValue)
 	}
 
-	queryParams := buildQueryParams(operation)
+	queryParams := c.buildQueryParams(operation)
 
 	for _, queryParam := range queryParams {
 		postmanURL.AddQueryParam(queryParam)
 	}
+	postmanURL.Raw = postmanURL.String()
 
 	return postmanURL
 }
 
-//buildQueryParams build postman query param from a swagger operation spec
-func buildQueryParams(operation *spec.Operation) []postman2.URLQueryParam {
+//isPostmanVariable checks if a string is a single postman variable, such as {{baseUrl}}
+func isPostmanVariable(s string) bool {
+	return strings.HasPrefix(s, "{{") && strings.HasSuffix(s, "}}") && strings.Count(s, "{{") == 1 && strings.Count(s, "}}") == 1
+}
 
-	var queryParam []postman2.URLQueryParam
+//templateURL turns the swagger templates of a url, such as {id}, into postman variables, such as {{id}}, and returns
+//the names of the templates, in order and without duplicates. Postman variables already in the url are kept as is.
+//Spaces are removed.
+func templateURL(url string) (string, []string) {
 
-	for _, param := range operation.Parameters {
-		if param.In == "query" {
+	url = strings.Replace(url, " ", "", -1)
 
-			if param.Example != nil {
-				queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: param.Example})
-				continue
-			}
+	var b strings.Builder
+	var variables []string
+	seen := map[string]bool{}
 
-			if param.Default != nil {
-				queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: param.Default})
+	for i := 0; i < len(url); {
+		if strings.HasPrefix(url[i:], "{{") {
+			if end := strings.Index(url[i+2:], "}}"); end >= 0 {
+				b.WriteString(url[i : i+end+4])
+				i += end + 4
 				continue
 			}
-
-			if len(param.Enum) > 0 {
-				queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: param.Enum[0]})
+		}
+		if url[i] == '{' {
+			end := strings.IndexAny(url[i+1:], "{}/?#")
+			if end > 0 && url[i+1+end] == '}' {
+				name := url[i+1 : i+1+end]
+				b.WriteString("{{" + name + "}}")
+				if !seen[name] {
+					seen[name] = true
+					variables = append(variables, name)
+				}
+				i += end + 2
 				continue
 			}
+		}
+		b.WriteByte(url[i])
+		i++
+	}
 
-			if param.Type == "array" {
-				if param.Items.Example != nil {
-					queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: param.Items.Example})
-					continue
-				}
-				if param.Items.Default != nil {
-					queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: param.Items.Default})
-					continue
-				}
+	return b.String(), variables
+}
 
-				if len(param.Items.Enum) > 0 {
-					queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: param.Items.Enum[0]})
-					continue
-				}
+//buildQueryParams build postman query param from a swagger operation spec.
+//Array and object values are encoded following the parameter collectionFormat, or its OpenAPI 3 style and explode
+//flags defined with "x-style" and "x-explode" extensions.
+//Optional params are disabled : they are listed in the request, without being sent.
+func (c *Converter) buildQueryParams(operation *spec.Operation) []postman2.URLQueryParam {
 
-				queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: buildQueryParamDefaultValue(param.Items.Type, param.Items.Format)})
-				continue
-			}
+	var queryParam []postman2.URLQueryParam
 
-			queryParam = append(queryParam, postman2.URLQueryParam{Key: param.Name, Value: buildQueryParamDefaultValue(param.Type, param.Format)})
+	for _, param := range operation.Parameters {
+		if param.In == "query" {
+			for _, query := range encodeQueryParam(param, c.queryParamValue(param)) {
+				query.Description = param.Description
+				query.Disabled = !param.Required
+				queryParam = append(queryParam, query)
+			}
 		}
 	}
 
@@ -148,54 +195,127 @@ func buildQueryParams(operation *spec.Operation) []postman2.URLQueryParam {
 
 }
 
-//buildQueryParamDefaultValue returns default values from a param type and format
-func buildQueryParamDefaultValue(propType string, propFormat string) interface{} {
+//queryParamValue returns the sample value of a query parameter
+func (c *Converter) queryParamValue(param spec.Parameter) interface{} {
+
+	if value, ok := postmanValue(param.Extensions, param.Type); ok {
+		return plainValue(value)
+	}
+
+	if param.Example != nil {
+		return param.Example
+	}
 
-	if propType == "" {
-		return ""
+	if param.Default != nil {
+		return param.Default
 	}
 
-	//Property has no example value : we set one by default
-	if propType == "integer" {
-		return 0
+	if len(param.Enum) > 0 {
+		return param.Enum[0]
 	}
 
-	if propType == "string" {
-		switch propFormat {
-		case "date-time":
-			return time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC).Format(time.RFC3339)
+	//arrays are sampled with two items, so that their collectionFormat or style shows in the url
+	if param.Type == "array" && param.Items != nil {
+		if len(param.Items.Enum) > 1 {
+			return []interface{}{param.Items.Enum[0], param.Items.Enum[1]}
+		}
+		var item interface{}
+		switch {
+		case param.Items.Example != nil:
+			item = param.Items.Example
+		case param.Items.Default != nil:
+			item = param.Items.Default
+		case len(param.Items.Enum) > 0:
+			item = param.Items.Enum[0]
 		default:
-			return "string"
+			item = plainValue(c.generateValue(param.Name, param.Items.Type, param.Items.Format))
 		}
+		return []interface{}{item, item}
 	}
 
-	return ""
+	return plainValue(c.generateValue(param.Name, param.Type, param.Format))
 }
 
-//pathHasMethodWithTag checks if a swagger path if defined for a given method (GET, PUT, POST, etc.)
-func pathHasMethodWithTag(path spec.PathItem, method string) bool {
-	method = strings.TrimSpace(strings.ToLower(method))
-	switch method {
-	case "get":
-		if path.Get != nil && len(path.Get.Tags) > 0 && len(strings.TrimSpace(path.Get.Tags[0])) > 0 {
-			return true
+const (
+	styleExtension   = "x-style"
+	explodeExtension = "x-explode"
+)
+
+//encodeQueryParam encodes the value of a query parameter as postman query params.
+//Arrays are joined with the separator of the collectionFormat (csv, ssv, tsv or pipes), or repeated for multi.
+//The OpenAPI 3 styles form, spaceDelimited, pipeDelimited and deepObject take precedence over the collectionFormat.
+func encodeQueryParam(param spec.Parameter, value interface{}) []postman2.URLQueryParam {
+
+	style, _ := param.Extensions.GetString(styleExtension)
+	explode, ok := param.Extensions[explodeExtension].(bool)
+	if !ok {
+		//form is the only style exploded by default
+		explode = style == "form"
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) == 1 {
+			return []postman2.URLQueryParam{{Key: param.Name, Value: v[0]}}
+		}
+
+		separator := ","
+		switch {
+		case style == "spaceDelimited":
+			separator = " "
+		case style == "pipeDelimited":
+			separator = "|"
+		case style != "":
+		case param.CollectionFormat == "ssv":
+			separator = " "
+		case param.CollectionFormat == "tsv":
+			separator = "\t"
+		case param.CollectionFormat == "pipes":
+			separator = "|"
+		case param.CollectionFormat == "multi":
+			explode = true
 		}
-	case "patch":
-		if path.Patch != nil && len(path.Patch.Tags) > 0 && len(strings.TrimSpace(path.Patch.Tags[0])) > 0 {
-			return true
+
+		if explode {
+			var params []postman2.URLQueryParam
+			for _, item := range v {
+				params = append(params, postman2.URLQueryParam{Key: param.Name, Value: item})
+			}
+			return params
+		}
+
+		var items []string
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
 		}
-	case "post":
-		if path.Post != nil && len(path.Post.Tags) > 0 && len(strings.TrimSpace(path.Post.Tags[0])) > 0 {
-			return true
+		return []postman2.URLQueryParam{{Key: param.Name, Value: strings.Join(items, separator)}}
+
+	case map[string]interface{}:
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
 		}
-	case "put":
-		if path.Put != nil && len(path.Put.Tags) > 0 && len(strings.TrimSpace(path.Put.Tags[0])) > 0 {
-			return true
+		sort.Strings(keys)
+
+		var params []postman2.URLQueryParam
+		var pairs []string
+
+		for _, key := range keys {
+			switch {
+			case style == "deepObject":
+				params = append(params, postman2.URLQueryParam{Key: param.Name + "[" + key + "]", Value: v[key]})
+			case explode:
+				params = append(params, postman2.URLQueryParam{Key: key, Value: v[key]})
+			default:
+				pairs = append(pairs, key, fmt.Sprint(v[key]))
+			}
 		}
-	case "delete":
-		if path.Delete != nil && len(path.Delete.Tags) > 0 && len(strings.TrimSpace(path.Delete.Tags[0])) > 0 {
-			return true
+
+		if len(params) > 0 {
+			return params
 		}
+		return []postman2.URLQueryParam{{Key: param.Name, Value: strings.Join(pairs, ",")}}
 	}
-	return false
+
+	return []postman2.URLQueryParam{{Key: param.Name, Value: value}}
 }
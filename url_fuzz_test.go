@@ -0,0 +1,35 @@
+//go:build go1.18
+// +build go1.18
+
+package postmanify
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzTemplateURL(f *testing.F) {
+
+	for _, seed := range []string{
+		"/users/{id}",
+		"/users/{id}{format}",
+		"/users/{{id}}/{name}",
+		"{region}.api.com:{port}",
+		"/{/}/{{}/{}}",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		url, variables := templateURL(raw)
+		for _, variable := range variables {
+			if !strings.Contains(url, "{{"+variable+"}}") {
+				t.Errorf("templateURL(%q) = %q does not contain the variable %q", raw, url, variable)
+			}
+		}
+		//postman variables are kept as is : a templated url is left unchanged
+		if again, _ := templateURL(url); again != url {
+			t.Errorf("templateURL(%q) is not stable: %q then %q", raw, url, again)
+		}
+	})
+}
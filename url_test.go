@@ -133,7 +133,7 @@ func TestBuildPostmanURL(t *testing.T) {
 					},
 					postman2.URLQueryParam{
 						Key:   "test2",
-						Value: 1,
+						Value: "1,2",
 					},
 				},
 			},
@@ -181,8 +181,10 @@ func TestBuildQueryParams(t *testing.T) {
 					Parameters: []spec.Parameter{
 						spec.Parameter{
 							ParamProps: spec.ParamProps{
-								Name: "test",
-								In:   "query",
+								Name:        "test",
+								In:          "query",
+								Required:    true,
+								Description: "a required param",
 							},
 							SimpleSchema: spec.SimpleSchema{
 								Type:    "string",
@@ -223,22 +225,164 @@ func TestBuildQueryParams(t *testing.T) {
 			},
 			expected: []postman2.URLQueryParam{
 				postman2.URLQueryParam{
-					Key:   "test",
-					Value: "test",
+					Key:         "test",
+					Value:       "test",
+					Description: "a required param",
 				},
 				postman2.URLQueryParam{
-					Key:   "testEnum",
-					Value: "test1",
+					Key:      "testEnum",
+					Value:    "test1",
+					Disabled: true,
 				},
 				postman2.URLQueryParam{
-					Key:   "test2",
-					Value: 1,
+					Key:      "test2",
+					Value:    "1,2",
+					Disabled: true,
 				},
 			},
 		},
 	}
 
 	for _, data := range dataset {
-		assert.Equal(t, data.expected, buildQueryParams(data.input))
+		conv := NewConverter(Config{})
+		assert.Equal(t, data.expected, conv.buildQueryParams(data.input))
+	}
+}
+
+func TestEncodeQueryParam(t *testing.T) {
+
+	param := func(collectionFormat string, extensions spec.Extensions) spec.Parameter {
+		return spec.Parameter{
+			ParamProps:       spec.ParamProps{Name: "status", In: "query"},
+			SimpleSchema:     spec.SimpleSchema{Type: "array", CollectionFormat: collectionFormat},
+			VendorExtensible: spec.VendorExtensible{Extensions: extensions},
+		}
+	}
+
+	values := []interface{}{"active", "pending"}
+	object := map[string]interface{}{"status": "active", "type": 2}
+
+	dataset := []struct {
+		param    spec.Parameter
+		value    interface{}
+		expected []postman2.URLQueryParam
+	}{
+		{param: param("", nil), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active,pending"}}},
+		{param: param("csv", nil), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active,pending"}}},
+		{param: param("ssv", nil), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active pending"}}},
+		{param: param("tsv", nil), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active\tpending"}}},
+		{param: param("pipes", nil), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active|pending"}}},
+		{param: param("multi", nil), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active"}, {Key: "status", Value: "pending"}}},
+		{param: param("multi", nil), value: []interface{}{1}, expected: []postman2.URLQueryParam{{Key: "status", Value: 1}}},
+		{param: param("", spec.Extensions{"x-style": "form"}), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active"}, {Key: "status", Value: "pending"}}},
+		{param: param("multi", spec.Extensions{"x-style": "form", "x-explode": false}), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active,pending"}}},
+		{param: param("", spec.Extensions{"x-style": "spaceDelimited"}), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active pending"}}},
+		{param: param("", spec.Extensions{"x-style": "pipeDelimited"}), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active|pending"}}},
+		{param: param("", spec.Extensions{"x-style": "pipeDelimited", "x-explode": true}), value: values, expected: []postman2.URLQueryParam{{Key: "status", Value: "active"}, {Key: "status", Value: "pending"}}},
+		{param: param("", spec.Extensions{"x-style": "deepObject", "x-explode": true}), value: object, expected: []postman2.URLQueryParam{{Key: "status[status]", Value: "active"}, {Key: "status[type]", Value: 2}}},
+		{param: param("", spec.Extensions{"x-style": "form"}), value: object, expected: []postman2.URLQueryParam{{Key: "status", Value: "active"}, {Key: "type", Value: 2}}},
+		{param: param("", spec.Extensions{"x-style": "form", "x-explode": false}), value: object, expected: []postman2.URLQueryParam{{Key: "status", Value: "status,active,type,2"}}},
+		{param: param("", nil), value: "active", expected: []postman2.URLQueryParam{{Key: "status", Value: "active"}}},
+	}
+
+	for _, data := range dataset {
+		assert.Equal(t, data.expected, encodeQueryParam(data.param, data.value))
+	}
+}
+
+func TestTemplateURL(t *testing.T) {
+
+	dataset := []struct {
+		input     string
+		expected  string
+		variables []string
+	}{
+		{input: "/users/{id}", expected: "/users/{{id}}", variables: []string{"id"}},
+		{input: "/users/{id}{format}", expected: "/users/{{id}}{{format}}", variables: []string{"id", "format"}},
+		{input: "/users/{id}.{format}/{id}", expected: "/users/{{id}}.{{format}}/{{id}}", variables: []string{"id", "format"}},
+		{input: "/users/{{id}}", expected: "/users/{{id}}"},
+		{input: "/users/{ id }", expected: "/users/{{id}}", variables: []string{"id"}},
+		{input: "/users/{}/{id/x}", expected: "/users/{}/{id/x}"},
+		{input: "{region}.api.com:{port}", expected: "{{region}}.api.com:{{port}}", variables: []string{"region", "port"}},
+	}
+
+	for _, data := range dataset {
+		url, variables := templateURL(data.input)
+		assert.Equal(t, data.expected, url)
+		assert.Equal(t, data.variables, variables)
+	}
+}
+
+func TestBuildPostmanURLHost(t *testing.T) {
+
+	dataset := []struct {
+		cfg      Config
+		url      string
+		expected postman2.URL
+	}{
+		{
+			cfg: Config{Hostname: "localhost:8080", Schema: "http"},
+			url: "/users/{id}",
+			expected: postman2.URL{
+				Raw: "http://localhost:8080/users/{{id}}", Protocol: "http", Host: []string{"localhost"}, Port: "8080",
+				Path: []string{"users", "{{id}}"}, Variable: []postman2.URLVariable{{ID: "id"}},
+			},
+		},
+		{
+			cfg: Config{Hostname: "[::1]:8080", BasePath: "/v1/", Schema: "https"},
+			url: "/",
+			expected: postman2.URL{
+				Raw: "https://[::1]:8080/v1", Protocol: "https", Host: []string{"[::1]"}, Port: "8080",
+				Path: []string{"v1"}, Variable: []postman2.URLVariable{},
+			},
+		},
+		{
+			cfg: Config{Hostname: "{region}.api.com", Schema: "https"},
+			url: "/files/{name}.{ext}",
+			expected: postman2.URL{
+				Raw: "https://{{region}}.api.com/files/{{name}}.{{ext}}", Protocol: "https", Host: []string{"{{region}}", "api", "com"},
+				Path: []string{"files", "{{name}}.{{ext}}"}, Variable: []postman2.URLVariable{{ID: "name"}, {ID: "ext"}},
+			},
+		},
+		{
+			cfg: Config{Hostname: "{{baseUrl}}"},
+			url: "/users",
+			expected: postman2.URL{
+				Raw: "{{baseUrl}}/users", Host: []string{"{{baseUrl}}"}, Path: []string{"users"}, Variable: []postman2.URLVariable{},
+			},
+		},
+	}
+
+	for _, data := range dataset {
+		conv := NewConverter(data.cfg)
+		assert.Equal(t, data.expected, conv.buildPostmanURL(data.url, &spec.Operation{}))
+	}
+}
+
+func TestBuildQueryParamsArrayFormats(t *testing.T) {
+
+	param := func(collectionFormat string, extensions spec.Extensions) spec.Parameter {
+		return spec.Parameter{
+			ParamProps:       spec.ParamProps{Name: "ids", In: "query", Required: true},
+			SimpleSchema:     spec.SimpleSchema{Type: "array", CollectionFormat: collectionFormat, Items: &spec.Items{SimpleSchema: spec.SimpleSchema{Type: "integer"}}},
+			VendorExtensible: spec.VendorExtensible{Extensions: extensions},
+		}
+	}
+
+	dataset := []struct {
+		param    spec.Parameter
+		expected string
+	}{
+		{param: param("", nil), expected: "http://api.example.com/items?ids=0,0"},
+		{param: param("pipes", nil), expected: "http://api.example.com/items?ids=0|0"},
+		{param: param("ssv", nil), expected: "http://api.example.com/items?ids=0 0"},
+		{param: param("multi", nil), expected: "http://api.example.com/items?ids=0&ids=0"},
+		{param: param("", spec.Extensions{"x-style": "form"}), expected: "http://api.example.com/items?ids=0&ids=0"},
+	}
+
+	for _, data := range dataset {
+		conv := NewConverter(Config{Hostname: "api.example.com", Schema: "http"})
+		url := conv.buildPostmanURL("/items", &spec.Operation{OperationProps: spec.OperationProps{Parameters: []spec.Parameter{data.param}}})
+		assert.Equal(t, data.expected, url.Raw)
 	}
 }
@@ -0,0 +1,238 @@
+package postmanify
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	//xmlRootName is the name of the root xml element when the schema defines none
+	xmlRootName = "root"
+)
+
+//buildXML renders a generated value as an xml document, following the xml object (name, prefix, namespace,
+//attribute and wrapped) of its swagger Schema. The root element is named after the definition of the schema, if any.
+func (c *Converter) buildXML(definition string, schema spec.Schema, value interface{}) (string, error) {
+
+	name := definition
+	if name == "" {
+		name = definitionName(schema.Ref)
+	}
+	if name == "" {
+		name = xmlRootName
+	}
+
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "\t")
+
+	if err := c.encodeXMLElement(enc, schema, name, value); err != nil {
+		return "", err
+	}
+
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+
+	return xml.Header + buf.String(), nil
+}
+
+//bodyDefinitions indexes the definitions referenced by the body parameters of a spec, by JSON pointer to the
+//parameter. References are dropped by the spec expansion : they are read from the spec before it is expanded.
+func bodyDefinitions(swag *spec.Swagger) map[string]string {
+
+	definitions := make(map[string]string)
+	if swag.Paths == nil {
+		return definitions
+	}
+
+	add := func(param spec.Parameter, location string) {
+		//parameters may reference a parameter defined at the root of the spec
+		if param.Ref.String() != "" && param.Ref.GetPointer() != nil {
+			if tokens := param.Ref.GetPointer().DecodedTokens(); len(tokens) == 2 && tokens[0] == "parameters" {
+				param = swag.Parameters[tokens[1]]
+			}
+		}
+		if param.In != "body" || param.Schema == nil {
+			return
+		}
+		if name := definitionName(param.Schema.Ref); name != "" {
+			definitions[location] = name
+		}
+	}
+
+	for url, path := range swag.Paths.Paths {
+		for i, param := range path.Parameters {
+			add(param, jsonPointer("paths", url, "parameters", strconv.Itoa(i)))
+		}
+
+		operations := []struct {
+			method    string
+			operation *spec.Operation
+		}{
+			{http.MethodGet, path.Get},
+			{http.MethodPatch, path.Patch},
+			{http.MethodPost, path.Post},
+			{http.MethodPut, path.Put},
+			{http.MethodDelete, path.Delete},
+		}
+		for _, op := range operations {
+			if op.operation == nil {
+				continue
+			}
+			for i, param := range op.operation.Parameters {
+				add(param, jsonPointer("paths", url, strings.ToLower(op.method), "parameters", strconv.Itoa(i)))
+			}
+		}
+	}
+
+	return definitions
+}
+
+//bodyDefinition returns the name of the definition referenced by the body parameter of the operation being
+//converted, if any
+func (c *Converter) bodyDefinition(operation *spec.Operation) string {
+	for i, param := range operation.Parameters {
+		if param.In == "body" && param.Schema != nil {
+			return c.bodyDefinitions[c.parameterLocation(i)]
+		}
+	}
+	return ""
+}
+
+//encodeXMLElement encodes a generated value as an xml element
+func (c *Converter) encodeXMLElement(enc *xml.Encoder, schema spec.Schema, name string, value interface{}) error {
+
+	schema = c.resolveSchema(schema)
+	start := xmlStartElement(schema, name)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return c.encodeXMLObject(enc, schema, start, v)
+	case []interface{}:
+		return c.encodeXMLArray(enc, schema, name, start, v)
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(xml.CharData(xmlText(value))); err != nil {
+		return err
+	}
+	return enc.EncodeToken(start.End())
+}
+
+//encodeXMLObject encodes a generated object as an xml element.
+//Properties flagged as xml attributes are encoded as attributes, other ones as child elements.
+func (c *Converter) encodeXMLObject(enc *xml.Encoder, schema spec.Schema, start xml.StartElement, object map[string]interface{}) error {
+
+	keys := []string{}
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var children []string
+
+	for _, key := range keys {
+		prop := c.resolveSchema(xmlPropertySchema(schema, key))
+		if prop.XML != nil && prop.XML.Attribute {
+			start.Attr = append(start.Attr, xml.Attr{Name: xmlStartElement(prop, key).Name, Value: xmlText(object[key])})
+			continue
+		}
+		children = append(children, key)
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, key := range children {
+		if err := c.encodeXMLElement(enc, xmlPropertySchema(schema, key), key, object[key]); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+//encodeXMLArray encodes a generated array as a list of xml elements, wrapped in a parent element if the schema
+//is flagged as wrapped. Items are named after their own xml object, or after the array name.
+func (c *Converter) encodeXMLArray(enc *xml.Encoder, schema spec.Schema, name string, start xml.StartElement, array []interface{}) error {
+
+	var items spec.Schema
+	if schema.Items != nil && schema.Items.Schema != nil {
+		items = *schema.Items.Schema
+	}
+
+	wrapped := schema.XML != nil && schema.XML.Wrapped
+
+	if wrapped {
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range array {
+		if err := c.encodeXMLElement(enc, items, name, item); err != nil {
+			return err
+		}
+	}
+
+	if wrapped {
+		return enc.EncodeToken(start.End())
+	}
+
+	return nil
+}
+
+//xmlStartElement builds the xml element of a schema, named after its xml object when defined
+func xmlStartElement(schema spec.Schema, name string) xml.StartElement {
+
+	if schema.XML == nil {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+
+	if schema.XML.Name != "" {
+		name = schema.XML.Name
+	}
+
+	var attrs []xml.Attr
+
+	if schema.XML.Prefix != "" {
+		if schema.XML.Namespace != "" {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + schema.XML.Prefix}, Value: schema.XML.Namespace})
+		}
+		name = schema.XML.Prefix + ":" + name
+	} else if schema.XML.Namespace != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: schema.XML.Namespace})
+	}
+
+	return xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs}
+}
+
+//xmlPropertySchema returns the schema of an object property, including map-style properties
+func xmlPropertySchema(schema spec.Schema, key string) spec.Schema {
+	if prop, ok := schema.Properties[key]; ok {
+		return prop
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		return *schema.AdditionalProperties.Schema
+	}
+	return spec.Schema{}
+}
+
+//xmlText renders a generated primitive value as xml text
+func xmlText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(plainValue(value))
+}
@@ -0,0 +1,137 @@
+package postmanify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/seblegall/postmanify/postman2"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildXML(t *testing.T) {
+
+	dataset := []struct {
+		input    spec.Schema
+		expected string
+	}{
+		{
+			input: spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"object"},
+					Properties: map[string]spec.Schema{
+						"id":   {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}}},
+						"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+					},
+				},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<root>
+	<id>0</id>
+	<name>string</name>
+</root>`,
+		},
+		{
+			input: spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"object"},
+					Properties: map[string]spec.Schema{
+						"id": {
+							SchemaProps:        spec.SchemaProps{Type: spec.StringOrArray{"integer"}},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{XML: &spec.XMLObject{Attribute: true}},
+						},
+						"tags": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"array"},
+								Items: &spec.SchemaOrArray{
+									Schema: &spec.Schema{
+										SchemaProps:        spec.SchemaProps{Type: spec.StringOrArray{"string"}},
+										SwaggerSchemaProps: spec.SwaggerSchemaProps{XML: &spec.XMLObject{Name: "tag"}},
+									},
+								},
+							},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{XML: &spec.XMLObject{Wrapped: true}},
+						},
+						"aliases": {SchemaProps: spec.SchemaProps{
+							Type:  spec.StringOrArray{"array"},
+							Items: &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}}},
+						}},
+					},
+				},
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{
+					XML: &spec.XMLObject{Name: "user", Prefix: "smp", Namespace: "http://example.com/schema"},
+				},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<smp:user xmlns:smp="http://example.com/schema" id="0">
+	<aliases>string</aliases>
+	<tags>
+		<tag>string</tag>
+	</tags>
+</smp:user>`,
+		},
+	}
+
+	for _, data := range dataset {
+		conv := NewConverter(Config{})
+		xml, err := conv.buildXML("", data.input, conv.buildSchemaValue(data.input, schemaTrail{}))
+		assert.NoError(t, err)
+		assert.Equal(t, data.expected, xml)
+	}
+}
+
+func TestConvertXMLDefinitionName(t *testing.T) {
+
+	swag := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "xml", "version": "1.0"},
+		"consumes": ["application/xml"],
+		"parameters": {
+			"dog": {"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Dog"}}
+		},
+		"paths": {
+			"/dogs": {
+				"parameters": [{"$ref": "#/parameters/dog"}],
+				"post": {
+					"tags": ["dogs"]
+				},
+				"put": {
+					"tags": ["dogs"],
+					"parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Cat"}}]
+				}
+			},
+			"/pets": {
+				"post": {
+					"tags": ["pets"],
+					"parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Pet"}}]
+				},
+				"put": {
+					"tags": ["pets"],
+					"parameters": [{"in": "body", "name": "body", "schema": {"type": "object", "properties": {"id": {"type": "integer"}}}}]
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"Dog": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"Cat": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"Id": {"type": "object", "properties": {"id": {"type": "integer"}}}
+		}
+	}`)
+
+	pman, _, err := NewConverter(Config{}).Convert(swag)
+	assert.NoError(t, err)
+
+	var collection postman2.Collection
+	assert.NoError(t, json.Unmarshal(pman, &collection))
+
+	//structurally identical definitions keep their own name, from the body parameter or the one of its path
+	dogs := collection.Item[0]
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Dog>\n\t<name>string</name>\n</Dog>", dogs.Item[0].Request.Body.Raw)
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Cat>\n\t<name>string</name>\n</Cat>", dogs.Item[1].Request.Body.Raw)
+
+	//an inline schema is not named after a definition, even an identical one
+	pets := collection.Item[1]
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Pet>\n\t<name>string</name>\n</Pet>", pets.Item[0].Request.Body.Raw)
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<root>\n\t<id>0</id>\n</root>", pets.Item[1].Request.Body.Raw)
+}